@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"os"
 
 	"github.com/benderr/gophermart/internal/app"
 	"github.com/benderr/gophermart/internal/config"
@@ -11,5 +14,13 @@ func main() {
 	conf := config.MustLoad()
 	ctx := context.Background()
 
+	if flag.Arg(0) == "reconcile" {
+		if err := app.Reconcile(ctx, conf, flag.Args()[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app.Run(ctx, conf)
 }
@@ -37,3 +37,282 @@ CREATE TABLE IF NOT EXISTS withdrawals
     processed_at TIMESTAMP DEFAULT NOW(),
     CONSTRAINT withdrawals_pkey PRIMARY KEY (id)
 );
+
+CREATE TABLE IF NOT EXISTS balance_lots
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    source text NOT NULL,
+    order_num text,
+    amount double precision NOT NULL,
+    remaining double precision NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    CONSTRAINT balance_lots_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS balance_lots_user_idx ON balance_lots (user_id, created_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS balance_lots_expires_idx ON balance_lots (expires_at) WHERE remaining > 0;
+
+CREATE TABLE IF NOT EXISTS balance_expirations
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    lot_id UUID NOT NULL REFERENCES balance_lots(id),
+    amount double precision NOT NULL,
+    expired_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT balance_expirations_pkey PRIMARY KEY (id)
+);
+
+ALTER TABLE balance ADD COLUMN IF NOT EXISTS held double precision DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS balance_holds
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    order_num text NOT NULL,
+    amount double precision NOT NULL,
+    status text NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    CONSTRAINT balance_holds_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS balance_holds_expires_idx ON balance_holds (expires_at) WHERE status = 'ACTIVE';
+
+CREATE TABLE IF NOT EXISTS transfers
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    from_user_id UUID NOT NULL REFERENCES users(id),
+    to_user_id UUID NOT NULL REFERENCES users(id),
+    amount double precision NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT transfers_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS transfers_from_idx ON transfers (from_user_id, created_at);
+CREATE INDEX IF NOT EXISTS transfers_to_idx ON transfers (to_user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS user_tiers
+(
+    user_id UUID NOT NULL REFERENCES users(id),
+    tier text NOT NULL,
+    progress double precision NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT user_tiers_pkey PRIMARY KEY (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS balance_adjustments
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    amount double precision NOT NULL DEFAULT 0,
+    withdrawn double precision NOT NULL DEFAULT 0,
+    reason text NOT NULL,
+    comment text NOT NULL DEFAULT '',
+    actor text NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT balance_adjustments_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS balance_adjustments_user_idx ON balance_adjustments (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS campaigns
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    name text NOT NULL,
+    kind text NOT NULL,
+    value double precision NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    first_order_only boolean NOT NULL DEFAULT false,
+    min_accrual double precision NOT NULL DEFAULT 0,
+    disabled boolean NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT campaigns_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS campaign_bonuses
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    order_num text NOT NULL REFERENCES orders(order_num),
+    amount double precision NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT campaign_bonuses_pkey PRIMARY KEY (id),
+    CONSTRAINT campaign_bonuses_order_key UNIQUE (campaign_id, order_num)
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code text UNIQUE;
+
+CREATE TABLE IF NOT EXISTS referrals
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    referrer_id UUID NOT NULL REFERENCES users(id),
+    referred_id UUID NOT NULL UNIQUE REFERENCES users(id),
+    status text NOT NULL,
+    order_num text,
+    referrer_bonus double precision NOT NULL DEFAULT 0,
+    referred_bonus double precision NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    rewarded_at TIMESTAMP,
+    CONSTRAINT referrals_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS referrals_referrer_idx ON referrals (referrer_id);
+
+ALTER TABLE balance ADD COLUMN IF NOT EXISTS debt double precision DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS clawbacks
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    order_num text NOT NULL REFERENCES orders(order_num),
+    amount double precision NOT NULL,
+    debited double precision NOT NULL DEFAULT 0,
+    debt double precision NOT NULL DEFAULT 0,
+    written_off double precision NOT NULL DEFAULT 0,
+    policy text NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT clawbacks_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS clawbacks_user_idx ON clawbacks (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS voucher_batches
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    name text NOT NULL,
+    amount double precision NOT NULL,
+    max_redemptions integer NOT NULL DEFAULT 1,
+    expires_at TIMESTAMP NOT NULL,
+    created_by text NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT voucher_batches_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS vouchers
+(
+    code text NOT NULL,
+    batch_id UUID NOT NULL REFERENCES voucher_batches(id),
+    redeemed integer NOT NULL DEFAULT 0,
+    CONSTRAINT vouchers_pkey PRIMARY KEY (code)
+);
+
+CREATE INDEX IF NOT EXISTS vouchers_batch_idx ON vouchers (batch_id);
+
+CREATE TABLE IF NOT EXISTS voucher_redemptions
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    code text NOT NULL REFERENCES vouchers(code),
+    user_id UUID NOT NULL REFERENCES users(id),
+    amount double precision NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT voucher_redemptions_pkey PRIMARY KEY (id),
+    CONSTRAINT voucher_redemptions_user_key UNIQUE (code, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    revoked_at TIMESTAMP,
+    CONSTRAINT sessions_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_idx ON sessions (user_id) WHERE revoked_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    session_id UUID NOT NULL REFERENCES sessions(id),
+    token_hash text NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT refresh_tokens_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS signing_keys
+(
+    id text NOT NULL,
+    algorithm text NOT NULL,
+    private_key bytea NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    retires_at TIMESTAMP,
+    CONSTRAINT signing_keys_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS login_attempts
+(
+    key text NOT NULL,
+    failures integer NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    blocked_until TIMESTAMP,
+    CONSTRAINT login_attempts_pkey PRIMARY KEY (key)
+);
+
+CREATE TABLE IF NOT EXISTS password_resets
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    token_hash text NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT password_resets_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS two_factor
+(
+    user_id UUID NOT NULL REFERENCES users(id),
+    secret text NOT NULL,
+    enabled boolean NOT NULL DEFAULT false,
+    require_for_withdrawals boolean NOT NULL DEFAULT false,
+    last_counter bigint NOT NULL DEFAULT 0,
+    failures integer NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    enabled_at TIMESTAMP,
+    CONSTRAINT two_factor_pkey PRIMARY KEY (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    id UUID NOT NULL DEFAULT gen_random_uuid() ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    code_hash text NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT recovery_codes_pkey PRIMARY KEY (id),
+    CONSTRAINT recovery_codes_user_code_key UNIQUE (user_id, code_hash)
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'user';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name text NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email text NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone text NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notify_email boolean NOT NULL DEFAULT true;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notify_sms boolean NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notify_promo boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS welcome_budget
+(
+    id integer NOT NULL DEFAULT 1,
+    spent double precision NOT NULL DEFAULT 0,
+    CONSTRAINT welcome_budget_pkey PRIMARY KEY (id),
+    CONSTRAINT welcome_budget_single CHECK (id = 1)
+);
+
+INSERT INTO welcome_budget (id, spent)
+SELECT 1, COALESCE(SUM(amount), 0) FROM balance_lots WHERE source = 'welcome'
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS registered_ip text;
+CREATE INDEX IF NOT EXISTS referrals_referrer_created_idx ON referrals (referrer_id, created_at);
+
+ALTER TABLE balance_lots ADD COLUMN IF NOT EXISTS credited double precision;
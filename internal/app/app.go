@@ -2,7 +2,13 @@ package app
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"net"
 	"net/http"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/benderr/gophermart/internal/config"
 	messageBroker "github.com/benderr/gophermart/internal/message_broker"
@@ -11,6 +17,7 @@ import (
 	accrualDelivery "github.com/benderr/gophermart/internal/domain/accrual/delivery"
 	acrualService "github.com/benderr/gophermart/internal/domain/accrual/services"
 	accrualUsecase "github.com/benderr/gophermart/internal/domain/accrual/usecase"
+	"github.com/benderr/gophermart/internal/domain/user"
 	userDelivery "github.com/benderr/gophermart/internal/domain/user/delivery"
 	userRepository "github.com/benderr/gophermart/internal/domain/user/repository"
 	userUsecase "github.com/benderr/gophermart/internal/domain/user/usecase"
@@ -20,19 +27,90 @@ import (
 	orderRepository "github.com/benderr/gophermart/internal/domain/orders/repository"
 	orderUsecase "github.com/benderr/gophermart/internal/domain/orders/usecase"
 
+	"github.com/benderr/gophermart/internal/domain/balance"
 	balanceDelivery "github.com/benderr/gophermart/internal/domain/balance/delivery"
 	balanceRepository "github.com/benderr/gophermart/internal/domain/balance/repository"
 	balanceUsecase "github.com/benderr/gophermart/internal/domain/balance/usecase"
 
+	"github.com/benderr/gophermart/internal/domain/hold"
+	holdRepository "github.com/benderr/gophermart/internal/domain/hold/repository"
+
+	pointsDelivery "github.com/benderr/gophermart/internal/domain/points/delivery"
+	pointsRepository "github.com/benderr/gophermart/internal/domain/points/repository"
+	pointsUsecase "github.com/benderr/gophermart/internal/domain/points/usecase"
+
 	withdrawDelivery "github.com/benderr/gophermart/internal/domain/withdrawal/delivery"
 	withdrawRepository "github.com/benderr/gophermart/internal/domain/withdrawal/repository"
 	withdrawUsecase "github.com/benderr/gophermart/internal/domain/withdrawal/usecase"
 
+	"github.com/benderr/gophermart/internal/domain/transfer"
+	transferDelivery "github.com/benderr/gophermart/internal/domain/transfer/delivery"
+	transferRepository "github.com/benderr/gophermart/internal/domain/transfer/repository"
+	transferUsecase "github.com/benderr/gophermart/internal/domain/transfer/usecase"
+
+	historyDelivery "github.com/benderr/gophermart/internal/domain/history/delivery"
+	historyRepository "github.com/benderr/gophermart/internal/domain/history/repository"
+	historyUsecase "github.com/benderr/gophermart/internal/domain/history/usecase"
+
+	"github.com/benderr/gophermart/internal/domain/tier"
+	tierDelivery "github.com/benderr/gophermart/internal/domain/tier/delivery"
+	tierRepository "github.com/benderr/gophermart/internal/domain/tier/repository"
+	tierUsecase "github.com/benderr/gophermart/internal/domain/tier/usecase"
+
+	campaignDelivery "github.com/benderr/gophermart/internal/domain/campaign/delivery"
+	campaignRepository "github.com/benderr/gophermart/internal/domain/campaign/repository"
+	campaignUsecase "github.com/benderr/gophermart/internal/domain/campaign/usecase"
+
+	"github.com/benderr/gophermart/internal/domain/referral"
+	referralDelivery "github.com/benderr/gophermart/internal/domain/referral/delivery"
+	referralRepository "github.com/benderr/gophermart/internal/domain/referral/repository"
+	referralUsecase "github.com/benderr/gophermart/internal/domain/referral/usecase"
+
+	"github.com/benderr/gophermart/internal/domain/welcome"
+	welcomeRepository "github.com/benderr/gophermart/internal/domain/welcome/repository"
+	welcomeUsecase "github.com/benderr/gophermart/internal/domain/welcome/usecase"
+
+	"github.com/benderr/gophermart/internal/domain/clawback"
+	clawbackDelivery "github.com/benderr/gophermart/internal/domain/clawback/delivery"
+	clawbackRepository "github.com/benderr/gophermart/internal/domain/clawback/repository"
+	clawbackUsecase "github.com/benderr/gophermart/internal/domain/clawback/usecase"
+
+	voucherDelivery "github.com/benderr/gophermart/internal/domain/voucher/delivery"
+	voucherRepository "github.com/benderr/gophermart/internal/domain/voucher/repository"
+	voucherUsecase "github.com/benderr/gophermart/internal/domain/voucher/usecase"
+
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	lockoutDelivery "github.com/benderr/gophermart/internal/domain/lockout/delivery"
+	lockoutRepository "github.com/benderr/gophermart/internal/domain/lockout/repository"
+	lockoutUsecase "github.com/benderr/gophermart/internal/domain/lockout/usecase"
+
+	twoFactorDelivery "github.com/benderr/gophermart/internal/domain/twofactor/delivery"
+	twoFactorRepository "github.com/benderr/gophermart/internal/domain/twofactor/repository"
+	twoFactorUsecase "github.com/benderr/gophermart/internal/domain/twofactor/usecase"
+
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	signingKeyDelivery "github.com/benderr/gophermart/internal/domain/signingkey/delivery"
+	signingKeyRepository "github.com/benderr/gophermart/internal/domain/signingkey/repository"
+	signingKeyUsecase "github.com/benderr/gophermart/internal/domain/signingkey/usecase"
+
+	tokenDelivery "github.com/benderr/gophermart/internal/domain/token/delivery"
+	tokenRepository "github.com/benderr/gophermart/internal/domain/token/repository"
+	tokenUsecase "github.com/benderr/gophermart/internal/domain/token/usecase"
+
+	adjustmentDelivery "github.com/benderr/gophermart/internal/domain/adjustment/delivery"
+	adjustmentRepository "github.com/benderr/gophermart/internal/domain/adjustment/repository"
+	adjustmentUsecase "github.com/benderr/gophermart/internal/domain/adjustment/usecase"
+	reconciliationDelivery "github.com/benderr/gophermart/internal/domain/reconciliation/delivery"
+	reconciliationRepository "github.com/benderr/gophermart/internal/domain/reconciliation/repository"
+	reconciliationUsecase "github.com/benderr/gophermart/internal/domain/reconciliation/usecase"
+
 	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/notifier"
+	"github.com/benderr/gophermart/internal/password"
+	"github.com/benderr/gophermart/internal/ratelimit"
 	"github.com/benderr/gophermart/internal/session"
 	"github.com/benderr/gophermart/internal/storage"
 	"github.com/go-playground/validator"
-	"github.com/golang-jwt/jwt/v5"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -49,27 +127,212 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return nil
 }
 
+// Адрес клиента для ограничений по IP. X-Forwarded-For задает клиент, поэтому заголовок
+// читаем, только если запрос пришел от доверенного прокси
+func ipExtractor(trusted []string, logger logger.Logger) echo.IPExtractor {
+	if len(trusted) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	opts := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range trusted {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			logger.Errorln("[CONFIG]: invalid trusted proxy", cidr, err)
+			panic(err)
+		}
+		opts = append(opts, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(opts...)
+}
+
 func Run(ctx context.Context, conf *config.Config) {
 	logger, sync := logger.New()
 	defer sync()
 
 	db := storage.MustLoad(ctx, conf, logger)
 
-	sessionManager := session.New(conf.SecretKey)
 	trsctr := transactor.New(db)
+
+	signingAlgorithm, err := signingkey.ParseAlgorithm(conf.JWTAlgorithm)
+	if err != nil {
+		logger.Errorln("[CONFIG]: invalid jwt algorithm", err)
+		panic(err)
+	}
+
+	keyUsecase := signingKeyUsecase.New(signingKeyRepository.New(db, logger), trsctr, signingAlgorithm, conf.JWTKeyRotation, conf.AccessTTL, logger)
+
+	//с пустым секретом jwt принимает подпись HS256 пустым ключом, такой токен может выпустить кто угодно
+	if signingAlgorithm == signingkey.HS256 && conf.SecretKey == "" {
+		logger.Errorln("[CONFIG]: KEY is required for HS256")
+		panic(errors.New("empty secret key"))
+	}
+
+	sessionManager := session.New(conf.SecretKey, nil, conf.AccessTTL, time.Time{})
+	if signingAlgorithm != signingkey.HS256 {
+		if conf.JWTKeyCheckInterval <= 0 {
+			logger.Errorln("[CONFIG]: JWT_KEY_CHECK_INTERVAL must be positive")
+			panic(errors.New("invalid key check interval"))
+		}
+
+		if err := keyUsecase.Rotate(ctx); err != nil {
+			logger.Errorln("[SIGNING KEYS]: failed to load keys", err)
+			panic(err)
+		}
+		sessionManager = session.New(conf.SecretKey, keyUsecase, conf.AccessTTL, conf.JWTLegacyUntil)
+	}
 	msgBroker := messageBroker.New(5, logger)
 	msgBroker.Run(ctx)
 
 	userRepo := userRepository.New(db, logger)
+	tokenRepo := tokenRepository.New(db, logger)
+	lockoutRepo := lockoutRepository.New(db, logger)
+	twoFactorRepo := twoFactorRepository.New(db, logger)
 	orderRepo := orderRepository.New(db, logger)
 	balanceRepo := balanceRepository.New(db, logger)
 	withdrawRepo := withdrawRepository.New(db, logger)
+	lotRepo := pointsRepository.New(db, logger)
+	holdRepo := holdRepository.New(db, logger)
+	transferRepo := transferRepository.New(db, logger)
+	historyRepo := historyRepository.New(db, logger)
+	tierRepo := tierRepository.New(db, logger)
+	adjustmentRepo := adjustmentRepository.New(db, logger)
+	campaignRepo := campaignRepository.New(db, logger)
+	referralRepo := referralRepository.New(db, logger)
+	welcomeRepo := welcomeRepository.New(db, logger)
+	clawbackRepo := clawbackRepository.New(db, logger)
+	voucherRepo := voucherRepository.New(db, logger)
+	reconciliationRepo := reconciliationRepository.New(db, logger)
 	accrualSrv := acrualService.New(string(conf.AccrualServer), logger)
 
-	userUsecase := userUsecase.New(userRepo, logger)
-	orderUsecase := orderUsecase.New(orderRepo, balanceRepo, trsctr, msgBroker, logger)
-	balanceUsecase := balanceUsecase.New(balanceRepo, withdrawRepo, trsctr, logger)
+	withdrawPolicy := balance.WithdrawPolicy{
+		Min:          conf.WithdrawMin,
+		Max:          conf.WithdrawMax,
+		DailyLimit:   conf.WithdrawDailyLimit,
+		MonthlyLimit: conf.WithdrawMonthlyLimit,
+	}
+
+	transferLimits := transfer.Limits{
+		Min:        conf.TransferMin,
+		Max:        conf.TransferMax,
+		DailyLimit: conf.TransferDailyLimit,
+	}
+
+	tiers, err := tier.Parse(conf.Tiers)
+	if err != nil {
+		logger.Errorln("[CONFIG]: invalid tiers", err)
+		panic(err)
+	}
+
+	tierBasis, err := tier.ParseBasis(conf.TierBasis)
+	if err != nil {
+		logger.Errorln("[CONFIG]: invalid tier basis", err)
+		panic(err)
+	}
+
+	referralProgram := referral.Program{
+		ReferrerBonus: conf.ReferralReferrerBonus,
+		ReferredBonus: conf.ReferralReferredBonus,
+		MaxPerUser:    conf.ReferralMaxPerUser,
+		MaxPerDay:     conf.ReferralMaxPerDay,
+		MinAccrual:    conf.ReferralMinAccrual,
+	}
+
+	welcomeProgram := welcome.Program{
+		Amount: conf.WelcomeBonus,
+		From:   conf.WelcomeFrom,
+		To:     conf.WelcomeTo,
+		Budget: conf.WelcomeBudget,
+	}
+
+	clawbackPolicy, err := clawback.ParsePolicy(conf.ClawbackPolicy)
+	if err != nil {
+		logger.Errorln("[CONFIG]: invalid clawback policy", err)
+		panic(err)
+	}
+
+	clawbackRules := clawback.Rules{
+		Policy:            clawbackPolicy,
+		WriteOffThreshold: conf.ClawbackWriteOffThreshold,
+	}
+
+	loginPolicy := lockout.Policy{
+		FreeAttempts: conf.LoginFreeAttempts,
+		BaseDelay:    conf.LoginBaseDelay,
+		MaxDelay:     conf.LoginMaxDelay,
+		MaxFailures:  conf.LoginMaxFailures,
+		LockDuration: conf.LoginLockDuration,
+		Window:       conf.LoginFailureWindow,
+	}
+
+	ipPolicy := loginPolicy
+	ipPolicy.FreeAttempts = conf.LoginIPFreeAttempts
+	ipPolicy.MaxFailures = conf.LoginIPMaxFailures
+
+	//ADMIN_USERS назначает роль администратора при старте, остальные роли выдаются через /api/admin
+	for _, id := range conf.AdminUsers {
+		if err := userRepo.SetRole(ctx, id, user.RoleAdmin); err != nil {
+			logger.Errorln("[CONFIG]: admin user", id, err)
+		}
+	}
+
+	hasher, err := password.New(password.Config{
+		Algorithm: password.Algorithm(conf.PasswordHash),
+		Argon2: password.Argon2Params{
+			Memory:  conf.PasswordArgon2Mem,
+			Time:    conf.PasswordArgon2Time,
+			Threads: conf.PasswordArgon2Par,
+		},
+		BcryptCost: conf.PasswordBcryptCost,
+	})
+	if err != nil {
+		logger.Errorln("[CONFIG]: invalid password hash settings", err)
+		panic(err)
+	}
+
+	hashWorkers := conf.PasswordWorkers
+	if hashWorkers <= 0 {
+		hashWorkers = runtime.NumCPU()
+	}
+	hashPool := password.NewPool(hasher, hashWorkers, conf.PasswordQueue)
+	expvar.Publish("password_pool", hashPool.Metrics())
+
+	ntf, err := notifier.New(conf.Notifier, conf.NotifierFile, logger)
+	if err != nil {
+		logger.Errorln("[CONFIG]: invalid notifier", err)
+		panic(err)
+	}
+
+	holdTTL := hold.TTLPolicy{
+		Default: conf.HoldTTL,
+		Max:     conf.HoldMaxTTL,
+	}
+
+	//без периодического снятия просроченные резервы навсегда останутся замороженными
+	if conf.HoldReleaseInterval <= 0 {
+		logger.Errorln("[CONFIG]: HOLD_RELEASE_INTERVAL must be positive")
+		panic(errors.New("invalid hold release interval"))
+	}
+
+	tokenUsecase := tokenUsecase.New(tokenRepo, userRepo, sessionManager, trsctr, conf.AccessTTL, conf.RefreshTTL, logger)
+	pointsUsecase := pointsUsecase.New(lotRepo, balanceRepo, trsctr, conf.PointsTTL, conf.PointsExpireWarn, logger)
+	tierUsecase := tierUsecase.New(tierRepo, tiers, tierBasis, conf.TierWindow, logger)
+	campaignUsecase := campaignUsecase.New(campaignRepo, balanceRepo, pointsUsecase, logger)
+	referralUsecase := referralUsecase.New(referralRepo, balanceRepo, pointsUsecase, trsctr, referralProgram, logger)
+	welcomeUsecase := welcomeUsecase.New(welcomeRepo, balanceRepo, pointsUsecase, welcomeProgram, logger)
+	lockoutUsecase := lockoutUsecase.New(lockoutRepo, userRepo, trsctr, loginPolicy, ipPolicy, logger)
+	twoFactorUsecase := twoFactorUsecase.New(twoFactorRepo, userRepo, trsctr, conf.TOTPIssuer, logger)
+	userUsecase := userUsecase.New(userRepo, referralUsecase, welcomeUsecase, lockoutUsecase, twoFactorUsecase, tokenUsecase, ntf, hashPool, trsctr, conf.PasswordResetTTL, logger)
+	clawbackUsecase := clawbackUsecase.New(clawbackRepo, balanceRepo, pointsUsecase, clawbackRules, logger)
+	orderUsecase := orderUsecase.New(orderRepo, balanceRepo, pointsUsecase, tierUsecase, campaignUsecase, referralUsecase, clawbackUsecase, trsctr, msgBroker, logger)
+	balanceUsecase := balanceUsecase.New(balanceRepo, withdrawRepo, holdRepo, pointsUsecase, trsctr, withdrawPolicy, holdTTL, logger)
 	withdrawUsecase := withdrawUsecase.New(withdrawRepo, logger)
+	transferUsecase := transferUsecase.New(transferRepo, balanceRepo, userRepo, pointsUsecase, trsctr, transferLimits, logger)
+	historyUsecase := historyUsecase.New(historyRepo, logger)
+	voucherUsecase := voucherUsecase.New(voucherRepo, balanceRepo, pointsUsecase, trsctr, logger)
+	adjustmentUsecase := adjustmentUsecase.New(adjustmentRepo, balanceRepo, userRepo, pointsUsecase, trsctr, logger)
+	reconciliationUsecase := reconciliationUsecase.New(reconciliationRepo, balanceRepo, adjustmentRepo, trsctr, logger)
 	accrualUsecase := accrualUsecase.New(orderRepo, accrualSrv, orderUsecase, logger)
 
 	accrualConsumer.RegisterHandler(accrualUsecase, msgBroker)
@@ -78,23 +341,96 @@ func Run(ctx context.Context, conf *config.Config) {
 	validate := validator.New()
 
 	e.Validator = &CustomValidator{validator: validate}
+	e.IPExtractor = ipExtractor(conf.TrustedProxies, logger)
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
 	publicGroup := e.Group("")
 
-	privateGroup := e.Group("", echojwt.WithConfig(echojwt.Config{
-		SigningKey:    []byte(conf.SecretKey),
-		NewClaimsFunc: func(c echo.Context) jwt.Claims { return new(session.UserClaims) },
-	}))
+	transport := session.NewHeaderTransport()
+	if conf.AuthCookie {
+		transport = session.NewCookieTransport(conf.AuthCookieSecure, conf.RefreshTTL)
+	}
+
+	privateMiddleware := []echo.MiddlewareFunc{echojwt.WithConfig(echojwt.Config{
+		TokenLookup:    transport.TokenLookup(),
+		ParseTokenFunc: sessionManager.TokenParser(tokenUsecase),
+	})}
+
+	privateGroup := e.Group("", append(privateMiddleware, transport.CSRF()...)...)
 
-	userDelivery.NewUserHandlers(publicGroup, userUsecase, sessionManager, logger)
+	userDelivery.NewUserHandlers(publicGroup, userUsecase, tokenUsecase, sessionManager, transport, logger)
+	userDelivery.NewAccountHandlers(privateGroup, userUsecase, sessionManager, tokenUsecase, transport, logger)
+	userDelivery.NewProfileHandlers(privateGroup, userUsecase, sessionManager, tierUsecase, balanceUsecase, logger)
+	tokenDelivery.NewTokenHandlers(publicGroup, privateGroup, tokenUsecase, sessionManager, transport, logger, transport.CSRF()...)
 	orderDelivery.NewOrderHandlers(privateGroup, orderUsecase, sessionManager, logger)
-	balanceDelivery.NewBalanceHandlers(privateGroup, balanceUsecase, sessionManager, logger)
+	//код 2FA для операций, которые выводят баллы со счета: списание, резерв и перевод
+	requireOTP := twoFactorDelivery.RequireForWithdrawals(twoFactorUsecase, sessionManager, logger)
+	balanceDelivery.NewBalanceHandlers(privateGroup, balanceUsecase, sessionManager, logger, requireOTP)
+	twoFactorDelivery.NewTwoFactorHandlers(privateGroup, twoFactorUsecase, sessionManager, logger)
 	withdrawDelivery.NewWithdrawHandlers(privateGroup, withdrawUsecase, sessionManager, logger)
+	transferDelivery.NewTransferHandlers(privateGroup, transferUsecase, sessionManager, logger, requireOTP)
+	historyDelivery.NewHistoryHandlers(privateGroup, historyUsecase, sessionManager, logger)
+	tierDelivery.NewTierHandlers(privateGroup, tierUsecase, sessionManager, logger)
+	referralDelivery.NewReferralHandlers(privateGroup, referralUsecase, sessionManager, logger)
+	clawbackDelivery.NewClawbackHandlers(privateGroup, clawbackUsecase, sessionManager, logger)
+
+	//при нулевом запасе лимитер с конечной частотой отклоняет все запросы
+	if conf.VoucherRedeemRate > 0 && conf.VoucherRedeemBurst < 1 {
+		logger.Errorln("[CONFIG]: VOUCHER_REDEEM_BURST must be at least 1")
+		panic(errors.New("invalid voucher redeem burst"))
+	}
+
+	redeemLimit := ratelimit.Every(conf.VoucherRedeemRate, time.Minute)
+	voucherDelivery.NewVoucherHandlers(privateGroup, voucherUsecase, sessionManager, logger,
+		ratelimit.Middleware(ratelimit.New(redeemLimit, conf.VoucherRedeemBurst), func(c echo.Context) string {
+			userid, _ := sessionManager.GetUserID(c)
+			return userid
+		}),
+		ratelimit.Middleware(ratelimit.New(redeemLimit, conf.VoucherRedeemBurst), func(c echo.Context) string {
+			return c.RealIP()
+		}))
+
+	staffGroup := privateGroup.Group("/api/admin", sessionManager.RequireRole(user.RoleSupport, user.RoleAdmin))
+
+	userDelivery.NewUserSupportHandlers(staffGroup, userUsecase, logger)
+	orderDelivery.NewOrderAdminHandlers(staffGroup, orderUsecase, logger)
+	balanceDelivery.NewBalanceAdminHandlers(staffGroup, balanceUsecase, logger)
+
+	adminGroup := privateGroup.Group("/api/admin", sessionManager.RequireRole(user.RoleAdmin))
+
+	userDelivery.NewUserAdminHandlers(adminGroup, userUsecase, sessionManager, logger)
+
+	//метрики expvar, в том числе пула хеширования паролей; в cmdline могут быть секреты, поэтому только администраторам
+	adminGroup.GET("/debug/vars", echo.WrapHandler(expvar.Handler()))
+
+	adjustmentDelivery.NewAdjustmentHandlers(adminGroup, adjustmentUsecase, sessionManager, logger)
+	campaignDelivery.NewCampaignHandlers(adminGroup, campaignUsecase, logger)
+	voucherDelivery.NewVoucherAdminHandlers(adminGroup, voucherUsecase, sessionManager, logger)
+	lockoutDelivery.NewLockoutHandlers(adminGroup, lockoutUsecase, sessionManager, logger)
+
+	if signingAlgorithm != signingkey.HS256 {
+		signingKeyDelivery.NewKeyHandlers(publicGroup, keyUsecase)
+
+		rotateTask := signingKeyDelivery.NewRotateTask(keyUsecase, conf.JWTKeyCheckInterval, logger)
+		rotateTask.Run(ctx)
+	}
 
 	acrualTask := accrualDelivery.New(accrualUsecase, msgBroker, logger)
 	acrualTask.Run(ctx)
 
+	if conf.PointsExpireInterval > 0 {
+		expireTask := pointsDelivery.New(pointsUsecase, conf.PointsExpireInterval, logger)
+		expireTask.Run(ctx)
+	}
+
+	releaseHoldsTask := balanceDelivery.NewReleaseHoldsTask(balanceUsecase, conf.HoldReleaseInterval, logger)
+	releaseHoldsTask.Run(ctx)
+
+	if conf.ReconcileInterval > 0 {
+		reconcileTask := reconciliationDelivery.NewReconcileTask(reconciliationUsecase, conf.ReconcileInterval, conf.ReconcileRepair, logger)
+		reconcileTask.Run(ctx)
+	}
+
 	e.Logger.Fatal(e.Start(string(conf.Server)))
 }
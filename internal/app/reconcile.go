@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+	"io"
+
+	"github.com/benderr/gophermart/internal/config"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/storage"
+	"github.com/benderr/gophermart/internal/transactor"
+
+	adjustmentRepository "github.com/benderr/gophermart/internal/domain/adjustment/repository"
+	balanceRepository "github.com/benderr/gophermart/internal/domain/balance/repository"
+	reconciliationDelivery "github.com/benderr/gophermart/internal/domain/reconciliation/delivery"
+	reconciliationRepository "github.com/benderr/gophermart/internal/domain/reconciliation/repository"
+	reconciliationUsecase "github.com/benderr/gophermart/internal/domain/reconciliation/usecase"
+)
+
+// Подкоманда reconcile: однократная сверка балансов без запуска сервера
+func Reconcile(ctx context.Context, conf *config.Config, args []string, out io.Writer) error {
+	logger, sync := logger.New()
+	defer sync()
+
+	db := storage.MustLoad(ctx, conf, logger)
+	defer db.Close()
+
+	reconciliationUsecase := reconciliationUsecase.New(
+		reconciliationRepository.New(db, logger),
+		balanceRepository.New(db, logger),
+		adjustmentRepository.New(db, logger),
+		transactor.New(db),
+		logger)
+
+	return reconciliationDelivery.RunCommand(ctx, reconciliationUsecase, args, out)
+}
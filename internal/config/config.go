@@ -5,6 +5,7 @@ import (
 	"flag"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 )
@@ -35,6 +36,112 @@ type Config struct {
 	DatabaseDsn   string        `env:"DATABASE_URI"`
 	AccrualServer ServerAddress `env:"ACCRUAL_SYSTEM_ADDRESS"`
 	SecretKey     string        `env:"KEY"`
+
+	//срок жизни токена доступа и токена обновления
+	AccessTTL  time.Duration `env:"ACCESS_TTL"`
+	RefreshTTL time.Duration `env:"REFRESH_TTL"`
+
+	//алгоритм подписи токенов: HS256 (секрет KEY), RS256 или EdDSA с ротацией ключей и публикацией в /.well-known/jwks.json
+	JWTAlgorithm        string        `env:"JWT_ALGORITHM"`
+	JWTKeyRotation      time.Duration `env:"JWT_KEY_ROTATION"`
+	JWTKeyCheckInterval time.Duration `env:"JWT_KEY_CHECK_INTERVAL"`
+	//до какого момента (RFC3339) принимать токены HS256 после перехода на RS256/EdDSA, по умолчанию не принимать;
+	//клиент со старым токеном получает 401 и обновляет его по токену обновления
+	JWTLegacyUntil time.Time `env:"JWT_LEGACY_UNTIL"`
+
+	//название сервиса в приложении-аутентификаторе
+	TOTPIssuer string `env:"TOTP_ISSUER"`
+
+	//срок действия токена сброса пароля
+	PasswordResetTTL time.Duration `env:"PASSWORD_RESET_TTL"`
+
+	//алгоритм хеширования паролей: argon2id или bcrypt. Хеши другим алгоритмом или с другими параметрами пересчитываются при входе
+	PasswordHash       string `env:"PASSWORD_HASH"`
+	PasswordArgon2Mem  uint32 `env:"PASSWORD_ARGON2_MEMORY"`
+	PasswordArgon2Time uint32 `env:"PASSWORD_ARGON2_TIME"`
+	PasswordArgon2Par  uint8  `env:"PASSWORD_ARGON2_PARALLELISM"`
+	PasswordBcryptCost int    `env:"PASSWORD_BCRYPT_COST"`
+
+	//одновременных вычислений хеша пароля (0 - по числу ядер) и ожидающих в очереди, сверх очереди ответ 503
+	PasswordWorkers int `env:"PASSWORD_WORKERS"`
+	PasswordQueue   int `env:"PASSWORD_QUEUE"`
+
+	//доставка уведомлений пользователям: log (только для разработки) или file (в файл NOTIFIER_FILE),
+	//по умолчанию уведомления не отправляются
+	Notifier     string `env:"NOTIFIER"`
+	NotifierFile string `env:"NOTIFIER_FILE"`
+
+	//задержки и блокировка после неудачных попыток входа, отдельно по логину и по IP
+	LoginFreeAttempts   int           `env:"LOGIN_FREE_ATTEMPTS"`
+	LoginBaseDelay      time.Duration `env:"LOGIN_BASE_DELAY"`
+	LoginMaxDelay       time.Duration `env:"LOGIN_MAX_DELAY"`
+	LoginMaxFailures    int           `env:"LOGIN_MAX_FAILURES"`
+	LoginLockDuration   time.Duration `env:"LOGIN_LOCK_DURATION"`
+	LoginFailureWindow  time.Duration `env:"LOGIN_FAILURE_WINDOW"`
+	LoginIPFreeAttempts int           `env:"LOGIN_IP_FREE_ATTEMPTS"`
+	LoginIPMaxFailures  int           `env:"LOGIN_IP_MAX_FAILURES"`
+
+	//подсети прокси (CIDR через запятую), которым доверяем X-Forwarded-For;
+	//пусто - адрес клиента берется из соединения, заголовок игнорируется
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
+
+	//передача токенов в HttpOnly cookie с защитой от CSRF вместо заголовка Authorization
+	AuthCookie       bool `env:"AUTH_COOKIE"`
+	AuthCookieSecure bool `env:"AUTH_COOKIE_SECURE"`
+
+	//правила списания баллов, 0 - без ограничений
+	WithdrawMin          float64 `env:"WITHDRAW_MIN"`
+	WithdrawMax          float64 `env:"WITHDRAW_MAX"`
+	WithdrawDailyLimit   float64 `env:"WITHDRAW_DAILY_LIMIT"`
+	WithdrawMonthlyLimit float64 `env:"WITHDRAW_MONTHLY_LIMIT"`
+
+	//срок жизни начисленных баллов, 0 - баллы не сгорают; интервал списания сгоревших 0 - списание отключено
+	PointsTTL            time.Duration `env:"POINTS_TTL"`
+	PointsExpireInterval time.Duration `env:"POINTS_EXPIRE_INTERVAL"`
+	PointsExpireWarn     time.Duration `env:"POINTS_EXPIRE_WARN"`
+
+	//резервирование баллов под оплату
+	HoldTTL             time.Duration `env:"HOLD_TTL"`
+	HoldMaxTTL          time.Duration `env:"HOLD_MAX_TTL"`
+	HoldReleaseInterval time.Duration `env:"HOLD_RELEASE_INTERVAL"`
+
+	//ограничения переводов между пользователями, 0 - без ограничений
+	TransferMin        float64 `env:"TRANSFER_MIN"`
+	TransferMax        float64 `env:"TRANSFER_MAX"`
+	TransferDailyLimit float64 `env:"TRANSFER_DAILY_LIMIT"`
+
+	//уровни лояльности вида "Bronze:0:1,Silver:1000:1.25", пусто - уровни отключены
+	Tiers      string        `env:"TIERS"`
+	TierBasis  string        `env:"TIER_BASIS"`
+	TierWindow time.Duration `env:"TIER_WINDOW"`
+
+	//периодическая сверка балансов, 0 - отключена
+	ReconcileInterval time.Duration `env:"RECONCILE_INTERVAL"`
+	ReconcileRepair   bool          `env:"RECONCILE_REPAIR"`
+
+	//реферальная программа, бонусы начисляются за первый обработанный заказ приглашенного
+	ReferralReferrerBonus float64 `env:"REFERRAL_REFERRER_BONUS"`
+	ReferralReferredBonus float64 `env:"REFERRAL_REFERRED_BONUS"`
+	ReferralMaxPerUser    int     `env:"REFERRAL_MAX_PER_USER"`
+	ReferralMaxPerDay     int     `env:"REFERRAL_MAX_PER_DAY"`
+	ReferralMinAccrual    float64 `env:"REFERRAL_MIN_ACCRUAL"`
+
+	//приветственный бонус при регистрации, 0 - отключен; период в RFC3339, бюджет 0 - без ограничения
+	WelcomeBonus  float64   `env:"WELCOME_BONUS"`
+	WelcomeFrom   time.Time `env:"WELCOME_FROM"`
+	WelcomeTo     time.Time `env:"WELCOME_TO"`
+	WelcomeBudget float64   `env:"WELCOME_BUDGET"`
+
+	//отзыв начислений за заказы, признанные недействительными после обработки: negative, block или writeoff
+	ClawbackPolicy            string  `env:"CLAWBACK_POLICY"`
+	ClawbackWriteOffThreshold float64 `env:"CLAWBACK_WRITEOFF_THRESHOLD"`
+
+	//попыток погасить ваучер в минуту на пользователя и на IP, 0 - без ограничения
+	VoucherRedeemRate  int `env:"VOUCHER_REDEEM_RATE"`
+	VoucherRedeemBurst int `env:"VOUCHER_REDEEM_BURST"`
+
+	//идентификаторы пользователей, которым при старте назначается роль администратора, через запятую
+	AdminUsers []string `env:"ADMIN_USERS" envSeparator:","`
 }
 
 var config = Config{
@@ -42,6 +149,52 @@ var config = Config{
 	AccrualServer: ":8081",
 	DatabaseDsn:   "",
 	SecretKey:     "",
+
+	AccessTTL:  15 * time.Minute,
+	RefreshTTL: 30 * 24 * time.Hour,
+
+	JWTAlgorithm:        "HS256",
+	JWTKeyRotation:      30 * 24 * time.Hour,
+	JWTKeyCheckInterval: time.Minute,
+
+	TOTPIssuer: "Gophermart",
+
+	PasswordResetTTL: 30 * time.Minute,
+
+	PasswordHash:       "argon2id",
+	PasswordArgon2Mem:  64 * 1024,
+	PasswordArgon2Time: 1,
+	PasswordArgon2Par:  2,
+	PasswordBcryptCost: 12,
+	PasswordQueue:      64,
+
+	NotifierFile: "notifications.log",
+
+	LoginFreeAttempts:   3,
+	LoginBaseDelay:      time.Second,
+	LoginMaxDelay:       time.Minute,
+	LoginMaxFailures:    10,
+	LoginLockDuration:   15 * time.Minute,
+	LoginFailureWindow:  time.Hour,
+	LoginIPFreeAttempts: 20,
+	LoginIPMaxFailures:  100,
+
+	AuthCookieSecure: true,
+
+	PointsExpireInterval: time.Hour,
+	PointsExpireWarn:     30 * 24 * time.Hour,
+
+	HoldTTL:             15 * time.Minute,
+	HoldMaxTTL:          24 * time.Hour,
+	HoldReleaseInterval: time.Minute,
+
+	TierBasis:  "lifetime",
+	TierWindow: 365 * 24 * time.Hour,
+
+	ClawbackPolicy: "block",
+
+	VoucherRedeemRate:  5,
+	VoucherRedeemBurst: 5,
 }
 
 func init() {
@@ -62,9 +215,21 @@ func MustLoad() *Config {
 		panic(err)
 	}
 
+	if err := validate(&config); err != nil {
+		panic(err)
+	}
+
 	return &config
 }
 
+// Проверка значений, с которыми сервис не может запуститься
+func validate(c *Config) error {
+	if c.PasswordQueue < 0 {
+		return errors.New("PASSWORD_QUEUE must not be negative")
+	}
+	return nil
+}
+
 func transformServerAddress(address *ServerAddress) {
 	if !strings.HasPrefix(address.String(), "https://") && !strings.HasPrefix(address.String(), "http://") {
 		*address = ServerAddress("http://" + address.String())
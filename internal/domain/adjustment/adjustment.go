@@ -0,0 +1,51 @@
+package adjustment
+
+import (
+	"errors"
+	"time"
+)
+
+// Причина ручной или автоматической корректировки баланса
+type Reason string
+
+const (
+	//начисление в качестве жеста доброй воли
+	GOODWILL Reason = "GOODWILL"
+	//компенсация за проблему с заказом или сервисом
+	COMPENSATION Reason = "COMPENSATION"
+	//исправление ошибочного начисления или списания
+	CORRECTION Reason = "CORRECTION"
+	//списание баллов, полученных мошенническим путем
+	FRAUD Reason = "FRAUD"
+	//исправление расхождения, найденного сверкой баланса
+	RECONCILIATION Reason = "RECONCILIATION"
+)
+
+// Причины, доступные для ручной корректировки администратором
+func (r Reason) Manual() bool {
+	switch r {
+	case GOODWILL, COMPENSATION, CORRECTION, FRAUD:
+		return true
+	}
+	return false
+}
+
+// Корректировка баланса. Amount меняет текущий баланс, Withdrawn - сумму списаний
+type Adjustment struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Amount    float64   `json:"amount"`
+	Withdrawn float64   `json:"withdrawn,omitempty"`
+	Reason    Reason    `json:"reason"`
+	Comment   string    `json:"comment"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var (
+	ErrInvalidAmount     = errors.New("amount must not be zero")
+	ErrInvalidReason     = errors.New("unknown reason code")
+	ErrCommentRequired   = errors.New("comment required")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+)
@@ -0,0 +1,99 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/adjustment"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type AdjustmentUsecase interface {
+	Adjust(ctx context.Context, actor string, userid string, amount float64, reason adjustment.Reason, comment string) (*adjustment.Adjustment, error)
+	GetByUser(ctx context.Context, userid string) ([]adjustment.Adjustment, error)
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type adjustmentHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	AdjustmentUsecase
+}
+
+type AdjustmentModel struct {
+	Amount  *float64 `json:"amount" validate:"required"`
+	Reason  string   `json:"reason" validate:"required"`
+	Comment string   `json:"comment" validate:"required"`
+}
+
+// Регистрирует обработчики в группе /api/admin, доступ к которой уже ограничен администраторами
+func NewAdjustmentHandlers(group *echo.Group, au AdjustmentUsecase, session SessionManager, logger logger.Logger) {
+	h := &adjustmentHandler{
+		AdjustmentUsecase: au,
+		session:           session,
+		logger:            logger,
+	}
+
+	group.POST("/users/:id/adjustments", h.AdjustHandler)
+	group.GET("/users/:id/adjustments", h.GetAdjustmentsHandler)
+}
+
+func (a *adjustmentHandler) AdjustHandler(c echo.Context) error {
+	var m AdjustmentModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	actor, err := a.session.GetUserID(c)
+	if err != nil {
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	created, err := a.Adjust(c.Request().Context(), actor, c.Param("id"), *m.Amount, adjustment.Reason(m.Reason), m.Comment)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, adjustment.ErrUserNotFound):
+			return c.JSON(http.StatusNotFound, httputils.Error(err.Error()))
+		case errors.Is(err, adjustment.ErrInsufficientFunds):
+			return c.JSON(http.StatusPaymentRequired, httputils.Error(err.Error()))
+		case errors.Is(err, adjustment.ErrInvalidAmount),
+			errors.Is(err, adjustment.ErrInvalidReason),
+			errors.Is(err, adjustment.ErrCommentRequired):
+			return c.JSON(http.StatusUnprocessableEntity, httputils.Error(err.Error()))
+		}
+
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	a.logger.Infow("[ADJUSTMENT SUCCESS]", "adjustment", created)
+
+	return c.JSON(http.StatusOK, created)
+}
+
+func (a *adjustmentHandler) GetAdjustmentsHandler(c echo.Context) error {
+	list, err := a.GetByUser(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if len(list) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
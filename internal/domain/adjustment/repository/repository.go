@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/benderr/gophermart/internal/domain/adjustment"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type adjustmentRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *adjustmentRepository {
+	return &adjustmentRepository{db: db, log: log}
+}
+
+func (a *adjustmentRepository) Create(ctx context.Context, adj *adjustment.Adjustment) (*adjustment.Adjustment, error) {
+	row := transactor.FromContext(ctx, a.db).QueryRowContext(ctx, `INSERT INTO balance_adjustments (user_id, amount, withdrawn, reason, comment, actor)
+	VALUES($1, $2, $3, $4, $5, $6)
+	RETURNING id, user_id, amount, withdrawn, reason, comment, actor, created_at`,
+		adj.UserID, adj.Amount, adj.Withdrawn, adj.Reason, adj.Comment, adj.Actor)
+
+	var created adjustment.Adjustment
+	err := row.Scan(&created.ID, &created.UserID, &created.Amount, &created.Withdrawn, &created.Reason, &created.Comment, &created.Actor, &created.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	a.log.Infow("[BALANCE ADJUSTED]", "adjustment", created)
+	return &created, nil
+}
+
+func (a *adjustmentRepository) GetByUser(ctx context.Context, userid string) ([]adjustment.Adjustment, error) {
+	list := make([]adjustment.Adjustment, 0)
+
+	rows, err := transactor.FromContext(ctx, a.db).QueryContext(ctx, `SELECT id, user_id, amount, withdrawn, reason, comment, actor, created_at
+	FROM balance_adjustments WHERE user_id=$1 ORDER BY created_at DESC`, userid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var adj adjustment.Adjustment
+		err = rows.Scan(&adj.ID, &adj.UserID, &adj.Amount, &adj.Withdrawn, &adj.Reason, &adj.Comment, &adj.Actor, &adj.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, adj)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
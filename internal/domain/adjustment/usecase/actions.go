@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/benderr/gophermart/internal/domain/adjustment"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type adjustmentUsecase struct {
+	adjustmentRepo AdjustmentRepo
+	balanceRepo    BalanceRepo
+	userRepo       UserRepo
+	points         Points
+	transactor     Transactor
+	logger         logger.Logger
+}
+
+func New(ar AdjustmentRepo, br BalanceRepo, ur UserRepo, pts Points, t Transactor, l logger.Logger) *adjustmentUsecase {
+	return &adjustmentUsecase{
+		adjustmentRepo: ar,
+		balanceRepo:    br,
+		userRepo:       ur,
+		points:         pts,
+		transactor:     t,
+		logger:         l}
+}
+
+// Ручная корректировка баланса администратором actor.
+// Положительная сумма начисляет баллы, отрицательная списывает их в пределах доступного остатка
+func (a *adjustmentUsecase) Adjust(ctx context.Context, actor string, userid string, amount float64, reason adjustment.Reason, comment string) (*adjustment.Adjustment, error) {
+	if amount == 0 {
+		return nil, adjustment.ErrInvalidAmount
+	}
+
+	if !reason.Manual() {
+		return nil, adjustment.ErrInvalidReason
+	}
+
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil, adjustment.ErrCommentRequired
+	}
+
+	var created *adjustment.Adjustment
+	err := a.transactor.Within(ctx, func(ctx context.Context) error {
+		_, err := a.userRepo.GetUserByID(ctx, userid)
+		if err != nil {
+			if errors.Is(err, user.ErrNotFound) {
+				return adjustment.ErrUserNotFound
+			}
+			return err
+		}
+
+		err = a.balanceRepo.Lock(ctx, userid)
+		if err != nil {
+			return err
+		}
+
+		if amount < 0 {
+			bal, err := a.balanceRepo.GetBalanceByUser(ctx, userid)
+			if err != nil {
+				return err
+			}
+			if bal.Spendable() < -amount {
+				return adjustment.ErrInsufficientFunds
+			}
+		}
+
+		created, err = a.adjustmentRepo.Create(ctx, &adjustment.Adjustment{
+			UserID:  userid,
+			Amount:  amount,
+			Reason:  reason,
+			Comment: comment,
+			Actor:   actor,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = a.balanceRepo.Adjust(ctx, userid, amount, 0)
+		if err != nil {
+			return err
+		}
+
+		//начисление не создает партию баллов, поэтому не сгорает;
+		//списание уменьшает партии, чтобы списанные баллы не сгорели повторно
+		if amount < 0 {
+			return a.points.Consume(ctx, userid, -amount)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (a *adjustmentUsecase) GetByUser(ctx context.Context, userid string) ([]adjustment.Adjustment, error) {
+	return a.adjustmentRepo.GetByUser(ctx, userid)
+}
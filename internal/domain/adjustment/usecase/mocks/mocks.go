@@ -0,0 +1,213 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/adjustment/usecase (interfaces: AdjustmentRepo,BalanceRepo,UserRepo,Points)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/adjustment/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/adjustment/usecase AdjustmentRepo,BalanceRepo,UserRepo,Points
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	adjustment "github.com/benderr/gophermart/internal/domain/adjustment"
+	balance "github.com/benderr/gophermart/internal/domain/balance"
+	user "github.com/benderr/gophermart/internal/domain/user"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdjustmentRepo is a mock of AdjustmentRepo interface.
+type MockAdjustmentRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdjustmentRepoMockRecorder
+}
+
+// MockAdjustmentRepoMockRecorder is the mock recorder for MockAdjustmentRepo.
+type MockAdjustmentRepoMockRecorder struct {
+	mock *MockAdjustmentRepo
+}
+
+// NewMockAdjustmentRepo creates a new mock instance.
+func NewMockAdjustmentRepo(ctrl *gomock.Controller) *MockAdjustmentRepo {
+	mock := &MockAdjustmentRepo{ctrl: ctrl}
+	mock.recorder = &MockAdjustmentRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdjustmentRepo) EXPECT() *MockAdjustmentRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAdjustmentRepo) Create(arg0 context.Context, arg1 *adjustment.Adjustment) (*adjustment.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*adjustment.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAdjustmentRepoMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAdjustmentRepo)(nil).Create), arg0, arg1)
+}
+
+// GetByUser mocks base method.
+func (m *MockAdjustmentRepo) GetByUser(arg0 context.Context, arg1 string) ([]adjustment.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", arg0, arg1)
+	ret0, _ := ret[0].([]adjustment.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockAdjustmentRepoMockRecorder) GetByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockAdjustmentRepo)(nil).GetByUser), arg0, arg1)
+}
+
+// MockBalanceRepo is a mock of BalanceRepo interface.
+type MockBalanceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceRepoMockRecorder
+}
+
+// MockBalanceRepoMockRecorder is the mock recorder for MockBalanceRepo.
+type MockBalanceRepoMockRecorder struct {
+	mock *MockBalanceRepo
+}
+
+// NewMockBalanceRepo creates a new mock instance.
+func NewMockBalanceRepo(ctrl *gomock.Controller) *MockBalanceRepo {
+	mock := &MockBalanceRepo{ctrl: ctrl}
+	mock.recorder = &MockBalanceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceRepo) EXPECT() *MockBalanceRepoMockRecorder {
+	return m.recorder
+}
+
+// Adjust mocks base method.
+func (m *MockBalanceRepo) Adjust(arg0 context.Context, arg1 string, arg2, arg3 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockBalanceRepoMockRecorder) Adjust(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockBalanceRepo)(nil).Adjust), arg0, arg1, arg2, arg3)
+}
+
+// GetBalanceByUser mocks base method.
+func (m *MockBalanceRepo) GetBalanceByUser(arg0 context.Context, arg1 string) (*balance.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceByUser", arg0, arg1)
+	ret0, _ := ret[0].(*balance.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceByUser indicates an expected call of GetBalanceByUser.
+func (mr *MockBalanceRepoMockRecorder) GetBalanceByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceByUser", reflect.TypeOf((*MockBalanceRepo)(nil).GetBalanceByUser), arg0, arg1)
+}
+
+// Lock mocks base method.
+func (m *MockBalanceRepo) Lock(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockBalanceRepoMockRecorder) Lock(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockBalanceRepo)(nil).Lock), arg0, arg1)
+}
+
+// MockUserRepo is a mock of UserRepo interface.
+type MockUserRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepoMockRecorder
+}
+
+// MockUserRepoMockRecorder is the mock recorder for MockUserRepo.
+type MockUserRepoMockRecorder struct {
+	mock *MockUserRepo
+}
+
+// NewMockUserRepo creates a new mock instance.
+func NewMockUserRepo(ctrl *gomock.Controller) *MockUserRepo {
+	mock := &MockUserRepo{ctrl: ctrl}
+	mock.recorder = &MockUserRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
+	return m.recorder
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserRepo) GetUserByID(arg0 context.Context, arg1 string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepoMockRecorder) GetUserByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepo)(nil).GetUserByID), arg0, arg1)
+}
+
+// MockPoints is a mock of Points interface.
+type MockPoints struct {
+	ctrl     *gomock.Controller
+	recorder *MockPointsMockRecorder
+}
+
+// MockPointsMockRecorder is the mock recorder for MockPoints.
+type MockPointsMockRecorder struct {
+	mock *MockPoints
+}
+
+// NewMockPoints creates a new mock instance.
+func NewMockPoints(ctrl *gomock.Controller) *MockPoints {
+	mock := &MockPoints{ctrl: ctrl}
+	mock.recorder = &MockPointsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPoints) EXPECT() *MockPointsMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockPoints) Consume(arg0 context.Context, arg1 string, arg2 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockPointsMockRecorder) Consume(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockPoints)(nil).Consume), arg0, arg1, arg2)
+}
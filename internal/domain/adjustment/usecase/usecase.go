@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/adjustment"
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type AdjustmentRepo interface {
+	Create(ctx context.Context, adj *adjustment.Adjustment) (*adjustment.Adjustment, error)
+	GetByUser(ctx context.Context, userid string) ([]adjustment.Adjustment, error)
+}
+
+type BalanceRepo interface {
+	Lock(ctx context.Context, userid string) error
+	GetBalanceByUser(ctx context.Context, userid string) (*balance.Balance, error)
+	Adjust(ctx context.Context, userid string, amount float64, withdrawn float64) error
+}
+
+type UserRepo interface {
+	GetUserByID(ctx context.Context, id string) (*user.User, error)
+}
+
+type Points interface {
+	Consume(ctx context.Context, userid string, amount float64) error
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
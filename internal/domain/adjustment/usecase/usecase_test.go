@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/adjustment"
+	"github.com/benderr/gophermart/internal/domain/adjustment/usecase"
+	"github.com/benderr/gophermart/internal/domain/adjustment/usecase/mocks"
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/user"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdjust(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdjustmentRepo := mocks.NewMockAdjustmentRepo(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	mockUserRepo := mocks.NewMockUserRepo(ctrl)
+	mockPoints := mocks.NewMockPoints(ctrl)
+	mockTransactor := mocktransactor.New()
+	mockLogger := mocklogger.New()
+	adjustmentUsecase := usecase.New(mockAdjustmentRepo, mockBalanceRepo, mockUserRepo, mockPoints, mockTransactor, mockLogger)
+
+	t.Run("Credit goodwill points", func(t *testing.T) {
+		userid := "userid"
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), userid).Return(&user.User{ID: userid}, nil)
+		mockBalanceRepo.EXPECT().Lock(gomock.Any(), userid).Return(nil)
+		mockAdjustmentRepo.EXPECT().Create(gomock.Any(), &adjustment.Adjustment{
+			UserID:  userid,
+			Amount:  50,
+			Reason:  adjustment.GOODWILL,
+			Comment: "delayed delivery",
+			Actor:   "admin",
+		}).Return(&adjustment.Adjustment{ID: "id", Amount: 50}, nil)
+		mockBalanceRepo.EXPECT().Adjust(gomock.Any(), userid, 50.0, 0.0).Return(nil)
+
+		created, err := adjustmentUsecase.Adjust(context.Background(), "admin", userid, 50, adjustment.GOODWILL, " delayed delivery ")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "id", created.ID)
+		}
+	})
+
+	t.Run("Debit consumes lots", func(t *testing.T) {
+		userid := "userid"
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), userid).Return(&user.User{ID: userid}, nil)
+		mockBalanceRepo.EXPECT().Lock(gomock.Any(), userid).Return(nil)
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{Current: 100}, nil)
+		mockAdjustmentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&adjustment.Adjustment{ID: "id", Amount: -30}, nil)
+		mockBalanceRepo.EXPECT().Adjust(gomock.Any(), userid, -30.0, 0.0).Return(nil)
+		mockPoints.EXPECT().Consume(gomock.Any(), userid, 30.0).Return(nil)
+
+		_, err := adjustmentUsecase.Adjust(context.Background(), "admin", userid, -30, adjustment.CORRECTION, "duplicate accrual")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Debit more than available", func(t *testing.T) {
+		userid := "userid"
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), userid).Return(&user.User{ID: userid}, nil)
+		mockBalanceRepo.EXPECT().Lock(gomock.Any(), userid).Return(nil)
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{Current: 100, Held: 80}, nil)
+
+		_, err := adjustmentUsecase.Adjust(context.Background(), "admin", userid, -30, adjustment.FRAUD, "fraud")
+
+		assert.ErrorIs(t, err, adjustment.ErrInsufficientFunds)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "missing").Return(nil, user.ErrNotFound)
+
+		_, err := adjustmentUsecase.Adjust(context.Background(), "admin", "missing", 10, adjustment.GOODWILL, "comment")
+
+		assert.ErrorIs(t, err, adjustment.ErrUserNotFound)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		_, err := adjustmentUsecase.Adjust(context.Background(), "admin", "userid", 0, adjustment.GOODWILL, "comment")
+		assert.ErrorIs(t, err, adjustment.ErrInvalidAmount)
+
+		_, err = adjustmentUsecase.Adjust(context.Background(), "admin", "userid", 10, adjustment.RECONCILIATION, "comment")
+		assert.ErrorIs(t, err, adjustment.ErrInvalidReason)
+
+		_, err = adjustmentUsecase.Adjust(context.Background(), "admin", "userid", 10, adjustment.GOODWILL, "  ")
+		assert.ErrorIs(t, err, adjustment.ErrCommentRequired)
+	})
+}
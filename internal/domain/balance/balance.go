@@ -1,11 +1,33 @@
 package balance
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type Balance struct {
 	Current   float64 `json:"current"`
 	Withdrawn float64 `json:"withdrawn"`
-	UserID    string  `json:"-"`
+	//зарезервировано под оплату и доступно для списания
+	Held      float64 `json:"held"`
+	Available float64 `json:"available"`
+	//баллы, которые сгорят в ближайшее время, и дата ближайшего сгорания
+	ExpiringSoon float64    `json:"expiring_soon"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	//долг после отзыва начислений, пока он не погашен, списания запрещены
+	Debt float64 `json:"debt"`
+	//сумма отозванных начислений за заказы, признанные недействительными
+	ClawedBack float64 `json:"clawed_back"`
+	UserID     string  `json:"-"`
+}
+
+// Баллы, которые можно списать или зарезервировать
+func (b *Balance) Spendable() float64 {
+	return b.Current - b.Held
+}
+
+func (b *Balance) Blocked() bool {
+	return b.Debt > 0
 }
 
 var (
@@ -15,4 +37,13 @@ var (
 	//неверный номер заказа
 	ErrInvalidOrder   = errors.New("invalid order")
 	ErrUnexpectedFlow = errors.New("user balance lost")
+	//сумма списания должна быть положительной
+	ErrInvalidAmount = errors.New("invalid withdrawal amount")
+	ErrBelowMinimum  = errors.New("withdrawal amount below minimum")
+	ErrAboveMaximum  = errors.New("withdrawal amount above maximum")
+	//превышены лимиты списаний за период
+	ErrDailyLimitExceeded   = errors.New("daily withdrawal limit exceeded")
+	ErrMonthlyLimitExceeded = errors.New("monthly withdrawal limit exceeded")
+	//списания запрещены до погашения долга
+	ErrBlocked = errors.New("withdrawals blocked until debt is repaid")
 )
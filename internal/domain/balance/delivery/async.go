@@ -0,0 +1,49 @@
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type HoldReleaser interface {
+	ReleaseExpiredHolds(ctx context.Context) (int, error)
+}
+
+type releaseHoldsTask struct {
+	releaser HoldReleaser
+	interval time.Duration
+	logger   logger.Logger
+}
+
+func NewReleaseHoldsTask(hr HoldReleaser, interval time.Duration, logger logger.Logger) *releaseHoldsTask {
+	return &releaseHoldsTask{
+		releaser: hr,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Периодически освобождает просроченные резервы, пока не будет отменен ctx
+func (r *releaseHoldsTask) Run(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := r.releaser.ReleaseExpiredHolds(ctx)
+				if err != nil {
+					r.logger.Errorln("release holds error", err)
+				}
+				if count > 0 {
+					r.logger.Infoln("[RELEASED EXPIRED HOLDS]", count)
+				}
+			}
+		}
+	}()
+}
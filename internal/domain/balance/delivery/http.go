@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/hold"
 	"github.com/benderr/gophermart/internal/httputils"
 	"github.com/benderr/gophermart/internal/logger"
 	moonvalidator "github.com/benderr/gophermart/internal/moon_validator"
@@ -15,6 +17,9 @@ import (
 type BalanceUsecase interface {
 	GetBalanceByUser(ctx context.Context, userid string) (*balance.Balance, error)
 	Withdraw(ctx context.Context, userid string, number string, withdraw float64) error
+	Hold(ctx context.Context, userid string, number string, sum float64, ttl time.Duration) (*hold.Hold, error)
+	CaptureHold(ctx context.Context, userid string, id string) (*hold.Hold, error)
+	ReleaseHold(ctx context.Context, userid string, id string) (*hold.Hold, error)
 }
 
 type SessionManager interface {
@@ -32,7 +37,14 @@ type WithdrawModel struct {
 	Sum   *float64 `json:"sum" validate:"required"`
 }
 
-func NewBalanceHandlers(group *echo.Group, bu BalanceUsecase, session SessionManager, l logger.Logger) {
+type HoldModel struct {
+	WithdrawModel
+	//срок резерва в секундах, если не указан - срок по умолчанию
+	TTL int64 `json:"ttl,omitempty" validate:"gte=0"`
+}
+
+// withdrawMiddleware применяется к операциям списания и резервирования
+func NewBalanceHandlers(group *echo.Group, bu BalanceUsecase, session SessionManager, l logger.Logger, withdrawMiddleware ...echo.MiddlewareFunc) {
 	h := &balanceHandler{
 		BalanceUsecase: bu,
 		session:        session,
@@ -42,7 +54,20 @@ func NewBalanceHandlers(group *echo.Group, bu BalanceUsecase, session SessionMan
 	g := group.Group("/api/user")
 
 	g.GET("/balance", h.GetBalanceHandler)
-	g.POST("/balance/withdraw", h.WithdrawHandler)
+	g.POST("/balance/withdraw", h.WithdrawHandler, withdrawMiddleware...)
+	g.POST("/balance/holds", h.CreateHoldHandler, withdrawMiddleware...)
+	g.POST("/balance/holds/:id/capture", h.CaptureHoldHandler)
+	g.POST("/balance/holds/:id/release", h.ReleaseHoldHandler)
+}
+
+// Просмотр баланса любого пользователя для поддержки и администраторов
+func NewBalanceAdminHandlers(group *echo.Group, bu BalanceUsecase, l logger.Logger) {
+	h := &balanceHandler{
+		BalanceUsecase: bu,
+		logger:         l,
+	}
+
+	group.GET("/users/:id/balance", h.GetUserBalanceHandler)
 }
 
 func (b *balanceHandler) GetBalanceHandler(c echo.Context) error {
@@ -64,6 +89,20 @@ func (b *balanceHandler) GetBalanceHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, bal)
 }
 
+func (b *balanceHandler) GetUserBalanceHandler(c echo.Context) error {
+	bal, err := b.GetBalanceByUser(c.Request().Context(), c.Param("id"))
+
+	if err != nil {
+		if errors.Is(err, balance.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, httputils.Error(err.Error()))
+		}
+		b.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, bal)
+}
+
 func (b *balanceHandler) WithdrawHandler(c echo.Context) error {
 
 	var w WithdrawModel
@@ -99,6 +138,10 @@ func (b *balanceHandler) WithdrawHandler(c echo.Context) error {
 			return c.JSON(http.StatusPaymentRequired, httputils.Error("insufficient funds"))
 		}
 
+		if status, ok := policyErrorStatus(err); ok {
+			return c.JSON(status, httputils.Error(err.Error()))
+		}
+
 		b.logger.Errorln(err)
 		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
 	}
@@ -107,3 +150,104 @@ func (b *balanceHandler) WithdrawHandler(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, httputils.Ok())
 }
+
+func (b *balanceHandler) CreateHoldHandler(c echo.Context) error {
+	var m HoldModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	err := moonvalidator.MoonValidator(m.Order)
+
+	if err != nil {
+		if errors.Is(err, moonvalidator.ErrInvalidNumber) {
+			return c.JSON(http.StatusUnprocessableEntity, httputils.Error("invalid order number"))
+		}
+		b.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	userid, err := b.session.GetUserID(c)
+	if err != nil {
+		b.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	created, err := b.Hold(c.Request().Context(), userid, m.Order, *m.Sum, time.Duration(m.TTL)*time.Second)
+
+	if err != nil {
+		if errors.Is(err, balance.ErrInsufficientFunds) {
+			return c.JSON(http.StatusPaymentRequired, httputils.Error("insufficient funds"))
+		}
+
+		if errors.Is(err, hold.ErrInvalidTTL) {
+			return c.JSON(http.StatusBadRequest, httputils.Error("invalid ttl"))
+		}
+
+		if status, ok := policyErrorStatus(err); ok {
+			return c.JSON(status, httputils.Error(err.Error()))
+		}
+
+		b.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	b.logger.Infow("[HOLD CREATED]", "hold", created)
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (b *balanceHandler) CaptureHoldHandler(c echo.Context) error {
+	return b.resolveHold(c, b.CaptureHold)
+}
+
+func (b *balanceHandler) ReleaseHoldHandler(c echo.Context) error {
+	return b.resolveHold(c, b.ReleaseHold)
+}
+
+func (b *balanceHandler) resolveHold(c echo.Context, resolve func(ctx context.Context, userid string, id string) (*hold.Hold, error)) error {
+	userid, err := b.session.GetUserID(c)
+	if err != nil {
+		b.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	resolved, err := resolve(c.Request().Context(), userid, c.Param("id"))
+
+	if err != nil {
+		if errors.Is(err, hold.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, httputils.Error("hold not found"))
+		}
+
+		if errors.Is(err, hold.ErrNotActive) {
+			return c.JSON(http.StatusConflict, httputils.Error("hold is not active"))
+		}
+
+		b.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	b.logger.Infow("[HOLD RESOLVED]", "hold", resolved)
+
+	return c.JSON(http.StatusOK, resolved)
+}
+
+// Ошибки правил списания отдаем клиенту как есть, с разными кодами ответа
+func policyErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, balance.ErrInvalidAmount),
+		errors.Is(err, balance.ErrBelowMinimum),
+		errors.Is(err, balance.ErrAboveMaximum):
+		return http.StatusUnprocessableEntity, true
+	case errors.Is(err, balance.ErrDailyLimitExceeded),
+		errors.Is(err, balance.ErrMonthlyLimitExceeded),
+		errors.Is(err, balance.ErrBlocked):
+		return http.StatusForbidden, true
+	}
+	return 0, false
+}
@@ -0,0 +1,59 @@
+package balance
+
+// Период, за который считается сумма списаний (аргумент date_trunc в postgres)
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodMonth Period = "month"
+)
+
+// Правила списания баллов. Нулевое значение поля отключает соответствующее правило.
+// Ограничения доли от стоимости заказа нет: в запросе на списание передаются только номер заказа и сумма,
+// стоимость заказа сервису неизвестна. Правило будет добавлено, когда появится доверенный источник стоимости
+type WithdrawPolicy struct {
+	Min          float64
+	Max          float64
+	DailyLimit   float64
+	MonthlyLimit float64
+}
+
+// Сумма списаний пользователя за текущие сутки и месяц
+type WithdrawStats struct {
+	Daily   float64
+	Monthly float64
+}
+
+// Проверка суммы одного списания, не требует обращения к хранилищу
+func (p WithdrawPolicy) CheckAmount(sum float64) error {
+	if sum <= 0 {
+		return ErrInvalidAmount
+	}
+
+	if p.Min > 0 && sum < p.Min {
+		return ErrBelowMinimum
+	}
+
+	if p.Max > 0 && sum > p.Max {
+		return ErrAboveMaximum
+	}
+
+	return nil
+}
+
+// Проверка лимитов с учетом уже совершенных списаний
+func (p WithdrawPolicy) CheckLimits(sum float64, stats WithdrawStats) error {
+	if p.DailyLimit > 0 && stats.Daily+sum > p.DailyLimit {
+		return ErrDailyLimitExceeded
+	}
+
+	if p.MonthlyLimit > 0 && stats.Monthly+sum > p.MonthlyLimit {
+		return ErrMonthlyLimitExceeded
+	}
+
+	return nil
+}
+
+func (p WithdrawPolicy) HasLimits() bool {
+	return p.DailyLimit > 0 || p.MonthlyLimit > 0
+}
@@ -0,0 +1,45 @@
+package balance_test
+
+import (
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithdrawPolicyCheckAmount(t *testing.T) {
+	policy := balance.WithdrawPolicy{
+		Min: 10,
+		Max: 500,
+	}
+
+	tests := []struct {
+		name string
+		sum  float64
+		err  error
+	}{
+		{name: "negative sum", sum: -5, err: balance.ErrInvalidAmount},
+		{name: "zero sum", sum: 0, err: balance.ErrInvalidAmount},
+		{name: "below minimum", sum: 5, err: balance.ErrBelowMinimum},
+		{name: "above maximum", sum: 600, err: balance.ErrAboveMaximum},
+		{name: "valid", sum: 300, err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, policy.CheckAmount(tt.sum))
+		})
+	}
+}
+
+func TestWithdrawPolicyCheckLimits(t *testing.T) {
+	policy := balance.WithdrawPolicy{
+		DailyLimit:   100,
+		MonthlyLimit: 1000,
+	}
+
+	assert.NoError(t, policy.CheckLimits(50, balance.WithdrawStats{Daily: 50, Monthly: 500}))
+	assert.Equal(t, balance.ErrDailyLimitExceeded, policy.CheckLimits(60, balance.WithdrawStats{Daily: 50, Monthly: 500}))
+	assert.Equal(t, balance.ErrMonthlyLimitExceeded, policy.CheckLimits(60, balance.WithdrawStats{Daily: 0, Monthly: 950}))
+	assert.NoError(t, balance.WithdrawPolicy{}.CheckLimits(1e9, balance.WithdrawStats{Daily: 1e9, Monthly: 1e9}))
+}
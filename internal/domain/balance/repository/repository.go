@@ -7,6 +7,7 @@ import (
 
 	"github.com/benderr/gophermart/internal/domain/balance"
 	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
 )
 
 type balanceRepository struct {
@@ -18,11 +19,13 @@ func New(db *sql.DB, log logger.Logger) *balanceRepository {
 	return &balanceRepository{db: db, log: log}
 }
 
-func (u *balanceRepository) GetBalanceByUser(ctx context.Context, tx *sql.Tx, userid string) (*balance.Balance, error) {
+func (u *balanceRepository) GetBalanceByUser(ctx context.Context, userid string) (*balance.Balance, error) {
 
-	row := tx.QueryRowContext(ctx, "SELECT user_id, current, withdrawn from balance WHERE user_id=$1", userid)
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, `SELECT user_id, current, withdrawn, held, debt,
+	(SELECT COALESCE(SUM(amount), 0) FROM clawbacks WHERE user_id=$1)
+	from balance WHERE user_id=$1`, userid)
 	var ord balance.Balance
-	err := row.Scan(&ord.UserID, &ord.Current, &ord.Withdrawn)
+	err := row.Scan(&ord.UserID, &ord.Current, &ord.Withdrawn, &ord.Held, &ord.Debt, &ord.ClawedBack)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, balance.ErrNotFound
@@ -34,17 +37,100 @@ func (u *balanceRepository) GetBalanceByUser(ctx context.Context, tx *sql.Tx, us
 	return &ord, nil
 }
 
-func (u *balanceRepository) Add(ctx context.Context, tx *sql.Tx, userid string, balance *float64) error {
-	_, err := tx.ExecContext(ctx, `INSERT INTO balance (user_id, current)
-	VALUES($1, $2) 
-	ON CONFLICT (user_id) 
-	DO UPDATE SET current=balance.current + $2`, userid, *balance)
+// Начисление. При наличии долга после отзыва начислений сначала погашается долг.
+// Возвращает сумму, которая попала на баланс; партию баллов нужно создавать только на нее
+func (u *balanceRepository) Add(ctx context.Context, userid string, balance *float64) (float64, error) {
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, `WITH prev AS (SELECT debt FROM balance WHERE user_id=$1 FOR UPDATE)
+	INSERT INTO balance (user_id, current)
+	VALUES($1, $2)
+	ON CONFLICT (user_id)
+	DO UPDATE SET current=balance.current + GREATEST($2 - balance.debt, 0), debt=GREATEST(balance.debt - $2, 0)
+	RETURNING COALESCE((SELECT debt FROM prev), 0) - balance.debt`, userid, *balance)
 	u.log.Infoln("[TRY ADD BALANCE]", *balance)
-	return err
+
+	var repaid float64
+	if err := row.Scan(&repaid); err != nil {
+		return 0, err
+	}
+	return *balance - repaid, nil
 }
 
-func (u *balanceRepository) Withdraw(ctx context.Context, tx *sql.Tx, userid string, withdrawn float64) error {
+func (u *balanceRepository) Withdraw(ctx context.Context, userid string, withdrawn float64) error {
 	u.log.Infoln("[TRY WITHDRAW]", withdrawn)
-	_, err := tx.ExecContext(ctx, `UPDATE balance SET withdrawn=balance.withdrawn + $1,  current=balance.current - $1 WHERE user_id=$2`, withdrawn, userid)
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE balance SET withdrawn=balance.withdrawn + $1,  current=balance.current - $1 WHERE user_id=$2`, withdrawn, userid)
+	return err
+}
+
+// Списывает сгоревшие баллы, но не трогает зарезервированные и не уводит баланс в минус.
+// Возвращает фактически списанную сумму и часть amount, оставленную на балансе из-за резерва
+func (u *balanceRepository) Expire(ctx context.Context, userid string, amount float64) (float64, float64, error) {
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, `UPDATE balance b SET current=LEAST(b.current, GREATEST(b.current - $1, b.held, 0))
+	FROM (SELECT current FROM balance WHERE user_id=$2 FOR UPDATE) prev
+	WHERE b.user_id=$2
+	RETURNING prev.current - b.current, b.current - LEAST(prev.current, GREATEST(prev.current - $1, 0))`, amount, userid)
+
+	var expired, held float64
+	err := row.Scan(&expired, &held)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	u.log.Infoln("[EXPIRE BALANCE]", userid, expired, held)
+	return expired, held, nil
+}
+
+func (u *balanceRepository) Hold(ctx context.Context, userid string, amount float64) error {
+	u.log.Infoln("[TRY HOLD]", amount)
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE balance SET held=balance.held + $1 WHERE user_id=$2`, amount, userid)
+	return err
+}
+
+func (u *balanceRepository) ReleaseHold(ctx context.Context, userid string, amount float64) error {
+	u.log.Infoln("[TRY RELEASE HOLD]", amount)
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE balance SET held=GREATEST(balance.held - $1, 0) WHERE user_id=$2`, amount, userid)
+	return err
+}
+
+// Подтверждение резерва: сумма снимается с резерва и списывается с баланса
+func (u *balanceRepository) CaptureHold(ctx context.Context, userid string, amount float64) error {
+	u.log.Infoln("[TRY CAPTURE HOLD]", amount)
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE balance SET held=GREATEST(balance.held - $1, 0), withdrawn=balance.withdrawn + $1, current=balance.current - $1 WHERE user_id=$2`, amount, userid)
+	return err
+}
+
+// Блокирует строку баланса пользователя до конца транзакции, при отсутствии строки создает ее
+func (u *balanceRepository) Lock(ctx context.Context, userid string) error {
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `INSERT INTO balance (user_id) VALUES($1) ON CONFLICT (user_id) DO NOTHING`, userid)
+	if err != nil {
+		return err
+	}
+
+	_, err = transactor.FromContext(ctx, u.db).ExecContext(ctx, `SELECT 1 FROM balance WHERE user_id=$1 FOR UPDATE`, userid)
+	return err
+}
+
+// Списание без учета в withdrawn, например при переводе другому пользователю
+func (u *balanceRepository) Debit(ctx context.Context, userid string, amount float64) error {
+	u.log.Infoln("[TRY DEBIT]", amount)
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE balance SET current=balance.current - $1 WHERE user_id=$2`, amount, userid)
+	return err
+}
+
+// Корректировка текущего баланса и суммы списаний на указанные величины
+func (u *balanceRepository) Adjust(ctx context.Context, userid string, amount float64, withdrawn float64) error {
+	u.log.Infoln("[TRY ADJUST]", userid, amount, withdrawn)
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `INSERT INTO balance (user_id, current, withdrawn)
+	VALUES($1, $2, $3)
+	ON CONFLICT (user_id)
+	DO UPDATE SET current=balance.current + $2, withdrawn=balance.withdrawn + $3`, userid, amount, withdrawn)
+	return err
+}
+
+// Отзыв начисления: debited списывается с баланса, debt добавляется к долгу
+func (u *balanceRepository) Clawback(ctx context.Context, userid string, debited float64, debt float64) error {
+	u.log.Infoln("[TRY CLAWBACK]", userid, debited, debt)
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE balance SET current=balance.current - $1, debt=balance.debt + $2 WHERE user_id=$3`, debited, debt, userid)
 	return err
 }
@@ -6,59 +6,135 @@ import (
 	"errors"
 
 	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/hold"
 	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
 )
 
 type balanceUsecase struct {
 	balanceRepo   BalanceRepo
 	withdrawsRepo WithdrawsRepo
+	holdRepo      HoldRepo
+	points        Points
 	transactor    Transactor
+	policy        balance.WithdrawPolicy
+	holdTTL       hold.TTLPolicy
 	logger        logger.Logger
 }
 
-func New(br BalanceRepo, wr WithdrawsRepo, t Transactor, l logger.Logger) *balanceUsecase {
+func New(br BalanceRepo, wr WithdrawsRepo, hr HoldRepo, pts Points, t Transactor, p balance.WithdrawPolicy, ht hold.TTLPolicy, l logger.Logger) *balanceUsecase {
 	return &balanceUsecase{
 		balanceRepo:   br,
 		withdrawsRepo: wr,
+		holdRepo:      hr,
+		points:        pts,
 		transactor:    t,
+		policy:        p,
+		holdTTL:       ht,
 		logger:        l}
 }
 
 func (b *balanceUsecase) Withdraw(ctx context.Context, userid string, number string, withdraw float64) error {
-	return b.transactor.Within(ctx, func(ctx context.Context, tx *sql.Tx) error {
+	if err := b.policy.CheckAmount(withdraw); err != nil {
+		return err
+	}
 
-		bal, err := b.balanceRepo.GetBalanceByUser(ctx, tx, userid)
+	return b.transactor.Within(ctx, func(ctx context.Context) error {
+		err := b.checkFunds(ctx, userid, withdraw)
+		if err != nil {
+			return err
+		}
+
+		err = b.withdrawsRepo.Create(ctx, userid, number, withdraw)
 
 		if err != nil {
-			if errors.Is(err, balance.ErrNotFound) {
-				return balance.ErrInsufficientFunds
-			}
 			return err
 		}
 
-		if bal == nil {
-			return balance.ErrUnexpectedFlow
+		err = b.balanceRepo.Withdraw(ctx, userid, withdraw)
+
+		if err != nil {
+			return err
 		}
 
-		if bal.Current < withdraw {
+		return b.points.Consume(ctx, userid, withdraw)
+	}, transactor.WithIsolation(sql.LevelSerializable))
+
+}
+
+// Проверяет, что списания не заблокированы долгом, доступных (не зарезервированных) баллов хватает
+// и лимиты списаний с учетом активных резервов не превышены
+func (b *balanceUsecase) checkFunds(ctx context.Context, userid string, sum float64) error {
+	bal, err := b.balanceRepo.GetBalanceByUser(ctx, userid)
+
+	if err != nil {
+		if errors.Is(err, balance.ErrNotFound) {
 			return balance.ErrInsufficientFunds
 		}
+		return err
+	}
+
+	if bal == nil {
+		return balance.ErrUnexpectedFlow
+	}
 
-		err = b.withdrawsRepo.Create(ctx, tx, userid, number, withdraw)
+	if bal.Blocked() {
+		return balance.ErrBlocked
+	}
 
+	if bal.Spendable() < sum {
+		return balance.ErrInsufficientFunds
+	}
+
+	return b.checkLimits(ctx, userid, sum)
+}
+
+func (b *balanceUsecase) checkLimits(ctx context.Context, userid string, sum float64) error {
+	if !b.policy.HasLimits() {
+		return nil
+	}
+
+	stats, err := b.getWithdrawStats(ctx, userid)
+	if err != nil {
+		return err
+	}
+
+	return b.policy.CheckLimits(sum, *stats)
+}
+
+// Сумма списаний за период вместе с активными резервами: резерв будет списан при подтверждении
+func (b *balanceUsecase) getWithdrawStats(ctx context.Context, userid string) (*balance.WithdrawStats, error) {
+	var stats balance.WithdrawStats
+	var err error
+
+	held, err := b.holdRepo.GetActiveSum(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.policy.DailyLimit > 0 {
+		stats.Daily, err = b.withdrawsRepo.GetSumByPeriod(ctx, userid, balance.PeriodDay)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		stats.Daily += held
+	}
 
-		return b.balanceRepo.Withdraw(ctx, tx, userid, withdraw)
-	})
+	if b.policy.MonthlyLimit > 0 {
+		stats.Monthly, err = b.withdrawsRepo.GetSumByPeriod(ctx, userid, balance.PeriodMonth)
+		if err != nil {
+			return nil, err
+		}
+		stats.Monthly += held
+	}
 
+	return &stats, nil
 }
 
 func (b *balanceUsecase) GetBalanceByUser(ctx context.Context, userid string) (*balance.Balance, error) {
 	var resBal *balance.Balance
-	err := b.transactor.Within(ctx, func(ctx context.Context, tx *sql.Tx) error {
-		bal, err := b.balanceRepo.GetBalanceByUser(ctx, tx, userid)
+	err := b.transactor.Within(ctx, func(ctx context.Context) error {
+		bal, err := b.balanceRepo.GetBalanceByUser(ctx, userid)
 		if err != nil {
 			if errors.Is(err, balance.ErrNotFound) {
 				resBal = &balance.Balance{Current: 0, Withdrawn: 0}
@@ -66,9 +142,17 @@ func (b *balanceUsecase) GetBalanceByUser(ctx context.Context, userid string) (*
 			}
 			return err
 		}
+		exp, err := b.points.GetExpiring(ctx, userid)
+		if err != nil {
+			return err
+		}
+
+		bal.Available = bal.Spendable()
+		bal.ExpiringSoon = exp.Amount
+		bal.ExpiresAt = exp.ExpiresAt
 		resBal = bal
 		return nil
-	})
+	}, transactor.ReadOnly())
 
 	return resBal, err
 }
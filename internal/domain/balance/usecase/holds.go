@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/hold"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+const releaseBatchSize = 100
+
+// Резервирует баллы под оплату заказа на срок ttl (0 - срок по умолчанию)
+func (b *balanceUsecase) Hold(ctx context.Context, userid string, number string, sum float64, ttl time.Duration) (*hold.Hold, error) {
+	if err := b.policy.CheckAmount(sum); err != nil {
+		return nil, err
+	}
+
+	ttl, err := b.holdTTL.Resolve(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *hold.Hold
+	err = b.transactor.Within(ctx, func(ctx context.Context) error {
+		err := b.checkFunds(ctx, userid, sum)
+		if err != nil {
+			return err
+		}
+
+		created, err = b.holdRepo.Create(ctx, userid, number, sum, ttl)
+		if err != nil {
+			return err
+		}
+
+		return b.balanceRepo.Hold(ctx, userid, sum)
+	}, transactor.WithIsolation(sql.LevelSerializable))
+
+	return created, err
+}
+
+// Подтверждает резерв: зарезервированная сумма списывается как обычное списание по заказу.
+// Лимиты проверяются повторно: резерв мог быть создан в прошлом периоде
+func (b *balanceUsecase) CaptureHold(ctx context.Context, userid string, id string) (*hold.Hold, error) {
+	var captured *hold.Hold
+	err := b.transactor.Within(ctx, func(ctx context.Context) error {
+		h, err := b.getActiveHold(ctx, userid, id)
+		if err != nil {
+			return err
+		}
+
+		//сумма резерва уже учтена в статистике как активный резерв
+		err = b.checkLimits(ctx, userid, 0)
+		if err != nil {
+			return err
+		}
+
+		err = b.withdrawsRepo.Create(ctx, userid, h.Order, h.Sum)
+		if err != nil {
+			return err
+		}
+
+		err = b.balanceRepo.CaptureHold(ctx, userid, h.Sum)
+		if err != nil {
+			return err
+		}
+
+		err = b.points.Consume(ctx, userid, h.Sum)
+		if err != nil {
+			return err
+		}
+
+		captured, err = b.holdRepo.UpdateStatus(ctx, h.ID, hold.CAPTURED)
+		return err
+	}, transactor.WithIsolation(sql.LevelSerializable))
+
+	return captured, err
+}
+
+func (b *balanceUsecase) ReleaseHold(ctx context.Context, userid string, id string) (*hold.Hold, error) {
+	var released *hold.Hold
+	err := b.transactor.Within(ctx, func(ctx context.Context) error {
+		h, err := b.getActiveHold(ctx, userid, id)
+		if err != nil {
+			return err
+		}
+
+		released, err = b.release(ctx, h, hold.RELEASED)
+		return err
+	})
+
+	return released, err
+}
+
+// Освобождает просроченные резервы пачками, возвращает количество освобожденных
+func (b *balanceUsecase) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		count := 0
+		err := b.transactor.Within(ctx, func(ctx context.Context) error {
+			list, err := b.holdRepo.GetExpired(ctx, releaseBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, h := range list {
+				h := h
+				if _, err := b.release(ctx, &h, hold.EXPIRED); err != nil {
+					return err
+				}
+			}
+
+			count = len(list)
+			return nil
+		})
+
+		total += count
+		if err != nil || count < releaseBatchSize {
+			return total, err
+		}
+	}
+}
+
+func (b *balanceUsecase) getActiveHold(ctx context.Context, userid string, id string) (*hold.Hold, error) {
+	h, err := b.holdRepo.GetByID(ctx, userid, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Status != hold.ACTIVE || h.Expired {
+		return nil, hold.ErrNotActive
+	}
+
+	return h, nil
+}
+
+func (b *balanceUsecase) release(ctx context.Context, h *hold.Hold, status hold.Status) (*hold.Hold, error) {
+	err := b.balanceRepo.ReleaseHold(ctx, h.UserID, h.Sum)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.holdRepo.UpdateStatus(ctx, h.ID, status)
+}
@@ -1,19 +1,21 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/benderr/gophermart/internal/domain/balance/usecase (interfaces: BalanceRepo,WithdrawsRepo)
+// Source: github.com/benderr/gophermart/internal/domain/balance/usecase (interfaces: BalanceRepo,WithdrawsRepo,HoldRepo,Points)
 //
 // Generated by this command:
 //
-//	mockgen -destination=internal/domain/balance/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/balance/usecase BalanceRepo,WithdrawsRepo
+//	mockgen -destination=internal/domain/balance/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/balance/usecase BalanceRepo,WithdrawsRepo,HoldRepo,Points
 //
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
 	context "context"
-	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	balance "github.com/benderr/gophermart/internal/domain/balance"
+	hold "github.com/benderr/gophermart/internal/domain/hold"
+	points "github.com/benderr/gophermart/internal/domain/points"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,46 +43,89 @@ func (m *MockBalanceRepo) EXPECT() *MockBalanceRepoMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockBalanceRepo) Add(arg0 context.Context, arg1 *sql.Tx, arg2 string, arg3 *float64) error {
+func (m *MockBalanceRepo) Add(arg0 context.Context, arg1 string, arg2 *float64) (float64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "Add", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockBalanceRepoMockRecorder) Add(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBalanceRepo)(nil).Add), arg0, arg1, arg2)
+}
+
+// CaptureHold mocks base method.
+func (m *MockBalanceRepo) CaptureHold(arg0 context.Context, arg1 string, arg2 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Add indicates an expected call of Add.
-func (mr *MockBalanceRepoMockRecorder) Add(arg0, arg1, arg2, arg3 any) *gomock.Call {
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockBalanceRepoMockRecorder) CaptureHold(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBalanceRepo)(nil).Add), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockBalanceRepo)(nil).CaptureHold), arg0, arg1, arg2)
 }
 
 // GetBalanceByUser mocks base method.
-func (m *MockBalanceRepo) GetBalanceByUser(arg0 context.Context, arg1 *sql.Tx, arg2 string) (*balance.Balance, error) {
+func (m *MockBalanceRepo) GetBalanceByUser(arg0 context.Context, arg1 string) (*balance.Balance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBalanceByUser", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetBalanceByUser", arg0, arg1)
 	ret0, _ := ret[0].(*balance.Balance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBalanceByUser indicates an expected call of GetBalanceByUser.
-func (mr *MockBalanceRepoMockRecorder) GetBalanceByUser(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockBalanceRepoMockRecorder) GetBalanceByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceByUser", reflect.TypeOf((*MockBalanceRepo)(nil).GetBalanceByUser), arg0, arg1)
+}
+
+// Hold mocks base method.
+func (m *MockBalanceRepo) Hold(arg0 context.Context, arg1 string, arg2 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hold", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Hold indicates an expected call of Hold.
+func (mr *MockBalanceRepoMockRecorder) Hold(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceByUser", reflect.TypeOf((*MockBalanceRepo)(nil).GetBalanceByUser), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hold", reflect.TypeOf((*MockBalanceRepo)(nil).Hold), arg0, arg1, arg2)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockBalanceRepo) ReleaseHold(arg0 context.Context, arg1 string, arg2 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockBalanceRepoMockRecorder) ReleaseHold(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockBalanceRepo)(nil).ReleaseHold), arg0, arg1, arg2)
 }
 
 // Withdraw mocks base method.
-func (m *MockBalanceRepo) Withdraw(arg0 context.Context, arg1 *sql.Tx, arg2 string, arg3 float64) error {
+func (m *MockBalanceRepo) Withdraw(arg0 context.Context, arg1 string, arg2 float64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Withdraw", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "Withdraw", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Withdraw indicates an expected call of Withdraw.
-func (mr *MockBalanceRepoMockRecorder) Withdraw(arg0, arg1, arg2, arg3 any) *gomock.Call {
+func (mr *MockBalanceRepoMockRecorder) Withdraw(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockBalanceRepo)(nil).Withdraw), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockBalanceRepo)(nil).Withdraw), arg0, arg1, arg2)
 }
 
 // MockWithdrawsRepo is a mock of WithdrawsRepo interface.
@@ -107,15 +152,180 @@ func (m *MockWithdrawsRepo) EXPECT() *MockWithdrawsRepoMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockWithdrawsRepo) Create(arg0 context.Context, arg1 *sql.Tx, arg2, arg3 string, arg4 float64) error {
+func (m *MockWithdrawsRepo) Create(arg0 context.Context, arg1, arg2 string, arg3 float64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockWithdrawsRepoMockRecorder) Create(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+func (mr *MockWithdrawsRepoMockRecorder) Create(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWithdrawsRepo)(nil).Create), arg0, arg1, arg2, arg3)
+}
+
+// GetSumByPeriod mocks base method.
+func (m *MockWithdrawsRepo) GetSumByPeriod(arg0 context.Context, arg1 string, arg2 balance.Period) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSumByPeriod", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSumByPeriod indicates an expected call of GetSumByPeriod.
+func (mr *MockWithdrawsRepoMockRecorder) GetSumByPeriod(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSumByPeriod", reflect.TypeOf((*MockWithdrawsRepo)(nil).GetSumByPeriod), arg0, arg1, arg2)
+}
+
+// MockHoldRepo is a mock of HoldRepo interface.
+type MockHoldRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldRepoMockRecorder
+}
+
+// MockHoldRepoMockRecorder is the mock recorder for MockHoldRepo.
+type MockHoldRepoMockRecorder struct {
+	mock *MockHoldRepo
+}
+
+// NewMockHoldRepo creates a new mock instance.
+func NewMockHoldRepo(ctrl *gomock.Controller) *MockHoldRepo {
+	mock := &MockHoldRepo{ctrl: ctrl}
+	mock.recorder = &MockHoldRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldRepo) EXPECT() *MockHoldRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockHoldRepo) Create(arg0 context.Context, arg1, arg2 string, arg3 float64, arg4 time.Duration) (*hold.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*hold.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockHoldRepoMockRecorder) Create(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHoldRepo)(nil).Create), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetActiveSum mocks base method.
+func (m *MockHoldRepo) GetActiveSum(arg0 context.Context, arg1 string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSum", arg0, arg1)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSum indicates an expected call of GetActiveSum.
+func (mr *MockHoldRepoMockRecorder) GetActiveSum(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSum", reflect.TypeOf((*MockHoldRepo)(nil).GetActiveSum), arg0, arg1)
+}
+
+// GetByID mocks base method.
+func (m *MockHoldRepo) GetByID(arg0 context.Context, arg1, arg2 string) (*hold.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*hold.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockHoldRepoMockRecorder) GetByID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHoldRepo)(nil).GetByID), arg0, arg1, arg2)
+}
+
+// GetExpired mocks base method.
+func (m *MockHoldRepo) GetExpired(arg0 context.Context, arg1 int) ([]hold.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", arg0, arg1)
+	ret0, _ := ret[0].([]hold.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockHoldRepoMockRecorder) GetExpired(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockHoldRepo)(nil).GetExpired), arg0, arg1)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockHoldRepo) UpdateStatus(arg0 context.Context, arg1 string, arg2 hold.Status) (*hold.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*hold.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockHoldRepoMockRecorder) UpdateStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockHoldRepo)(nil).UpdateStatus), arg0, arg1, arg2)
+}
+
+// MockPoints is a mock of Points interface.
+type MockPoints struct {
+	ctrl     *gomock.Controller
+	recorder *MockPointsMockRecorder
+}
+
+// MockPointsMockRecorder is the mock recorder for MockPoints.
+type MockPointsMockRecorder struct {
+	mock *MockPoints
+}
+
+// NewMockPoints creates a new mock instance.
+func NewMockPoints(ctrl *gomock.Controller) *MockPoints {
+	mock := &MockPoints{ctrl: ctrl}
+	mock.recorder = &MockPointsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPoints) EXPECT() *MockPointsMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockPoints) Consume(arg0 context.Context, arg1 string, arg2 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockPointsMockRecorder) Consume(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockPoints)(nil).Consume), arg0, arg1, arg2)
+}
+
+// GetExpiring mocks base method.
+func (m *MockPoints) GetExpiring(arg0 context.Context, arg1 string) (*points.Expiring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiring", arg0, arg1)
+	ret0, _ := ret[0].(*points.Expiring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiring indicates an expected call of GetExpiring.
+func (mr *MockPointsMockRecorder) GetExpiring(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWithdrawsRepo)(nil).Create), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiring", reflect.TypeOf((*MockPoints)(nil).GetExpiring), arg0, arg1)
 }
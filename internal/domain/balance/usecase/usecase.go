@@ -2,21 +2,41 @@ package usecase
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
 	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/hold"
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/transactor"
 )
 
 type BalanceRepo interface {
-	Add(ctx context.Context, tx *sql.Tx, userid string, balance *float64) error
-	GetBalanceByUser(ctx context.Context, tx *sql.Tx, userid string) (*balance.Balance, error)
-	Withdraw(ctx context.Context, tx *sql.Tx, userid string, withdrawn float64) error
+	Add(ctx context.Context, userid string, balance *float64) (float64, error)
+	GetBalanceByUser(ctx context.Context, userid string) (*balance.Balance, error)
+	Withdraw(ctx context.Context, userid string, withdrawn float64) error
+	Hold(ctx context.Context, userid string, amount float64) error
+	ReleaseHold(ctx context.Context, userid string, amount float64) error
+	CaptureHold(ctx context.Context, userid string, amount float64) error
 }
 
 type WithdrawsRepo interface {
-	Create(ctx context.Context, tx *sql.Tx, userid string, number string, sum float64) error
+	Create(ctx context.Context, userid string, number string, sum float64) error
+	GetSumByPeriod(ctx context.Context, userid string, period balance.Period) (float64, error)
+}
+
+type HoldRepo interface {
+	Create(ctx context.Context, userid string, order string, sum float64, ttl time.Duration) (*hold.Hold, error)
+	GetByID(ctx context.Context, userid string, id string) (*hold.Hold, error)
+	GetActiveSum(ctx context.Context, userid string) (float64, error)
+	GetExpired(ctx context.Context, limit int) ([]hold.Hold, error)
+	UpdateStatus(ctx context.Context, id string, status hold.Status) (*hold.Hold, error)
 }
 
 type Transactor interface {
-	Within(ctx context.Context, tFunc func(ctx context.Context, tx *sql.Tx) error) error
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
+
+type Points interface {
+	Consume(ctx context.Context, userid string, amount float64) error
+	GetExpiring(ctx context.Context, userid string) (*points.Expiring, error)
 }
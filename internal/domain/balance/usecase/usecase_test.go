@@ -7,6 +7,7 @@ import (
 	"github.com/benderr/gophermart/internal/domain/balance"
 	"github.com/benderr/gophermart/internal/domain/balance/usecase"
 	"github.com/benderr/gophermart/internal/domain/balance/usecase/mocks"
+	"github.com/benderr/gophermart/internal/domain/hold"
 	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
 	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
 	"github.com/stretchr/testify/assert"
@@ -19,23 +20,27 @@ func TestWithdraw(t *testing.T) {
 
 	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
 	mockWithdrawsRepo := mocks.NewMockWithdrawsRepo(ctrl)
+	mockHoldRepo := mocks.NewMockHoldRepo(ctrl)
+	mockPoints := mocks.NewMockPoints(ctrl)
 	mockTransactor := mocktransactor.New()
 	mockLogger := mocklogger.New()
-	balanceUsecase := usecase.New(mockBalanceRepo, mockWithdrawsRepo, mockTransactor, mockLogger)
+	balanceUsecase := usecase.New(mockBalanceRepo, mockWithdrawsRepo, mockHoldRepo, mockPoints, mockTransactor, balance.WithdrawPolicy{}, hold.TTLPolicy{}, mockLogger)
 
 	t.Run("Withdraw success", func(t *testing.T) {
 
 		userid := "testuserid"
 		ordernum := "ordernum"
 		var withdraw float64 = 65
-		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), gomock.Any(), userid).Return(&balance.Balance{
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{
 			Current:   100,
 			Withdrawn: 20,
 		}, nil)
 
-		mockWithdrawsRepo.EXPECT().Create(gomock.Any(), gomock.Any(), userid, ordernum, withdraw).Return(nil)
+		mockWithdrawsRepo.EXPECT().Create(gomock.Any(), userid, ordernum, withdraw).Return(nil)
 
-		mockBalanceRepo.EXPECT().Withdraw(gomock.Any(), gomock.Any(), userid, withdraw).Return(nil)
+		mockBalanceRepo.EXPECT().Withdraw(gomock.Any(), userid, withdraw).Return(nil)
+
+		mockPoints.EXPECT().Consume(gomock.Any(), userid, withdraw).Return(nil)
 
 		err := balanceUsecase.Withdraw(context.Background(), userid, ordernum, withdraw)
 
@@ -47,7 +52,7 @@ func TestWithdraw(t *testing.T) {
 		userid := "testuserid"
 		ordernum := "ordernum"
 		var withdraw float64 = 120
-		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), gomock.Any(), userid).Return(&balance.Balance{
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{
 			Current:   100,
 			Withdrawn: 20,
 		}, nil)
@@ -59,4 +64,154 @@ func TestWithdraw(t *testing.T) {
 		}
 	})
 
+	t.Run("Withdraw error funds held", func(t *testing.T) {
+
+		userid := "testuserid"
+		ordernum := "ordernum"
+		var withdraw float64 = 50
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{
+			Current: 100,
+			Held:    60,
+		}, nil)
+
+		err := balanceUsecase.Withdraw(context.Background(), userid, ordernum, withdraw)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, balance.ErrInsufficientFunds, err)
+		}
+	})
+
+	t.Run("Withdraw error blocked by debt", func(t *testing.T) {
+
+		userid := "testuserid"
+		ordernum := "ordernum"
+		var withdraw float64 = 10
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{
+			Current: 100,
+			Debt:    30,
+		}, nil)
+
+		err := balanceUsecase.Withdraw(context.Background(), userid, ordernum, withdraw)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, balance.ErrBlocked, err)
+		}
+	})
+}
+
+func TestCaptureHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	mockWithdrawsRepo := mocks.NewMockWithdrawsRepo(ctrl)
+	mockHoldRepo := mocks.NewMockHoldRepo(ctrl)
+	mockPoints := mocks.NewMockPoints(ctrl)
+	mockTransactor := mocktransactor.New()
+	mockLogger := mocklogger.New()
+	balanceUsecase := usecase.New(mockBalanceRepo, mockWithdrawsRepo, mockHoldRepo, mockPoints, mockTransactor, balance.WithdrawPolicy{}, hold.TTLPolicy{}, mockLogger)
+
+	t.Run("Capture success", func(t *testing.T) {
+		userid := "testuserid"
+		h := &hold.Hold{ID: "holdid", UserID: userid, Order: "ordernum", Sum: 40, Status: hold.ACTIVE}
+
+		mockHoldRepo.EXPECT().GetByID(gomock.Any(), userid, h.ID).Return(h, nil)
+		mockWithdrawsRepo.EXPECT().Create(gomock.Any(), userid, h.Order, h.Sum).Return(nil)
+		mockBalanceRepo.EXPECT().CaptureHold(gomock.Any(), userid, h.Sum).Return(nil)
+		mockPoints.EXPECT().Consume(gomock.Any(), userid, h.Sum).Return(nil)
+		mockHoldRepo.EXPECT().UpdateStatus(gomock.Any(), h.ID, hold.CAPTURED).Return(&hold.Hold{ID: h.ID, Status: hold.CAPTURED}, nil)
+
+		captured, err := balanceUsecase.CaptureHold(context.Background(), userid, h.ID)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, hold.CAPTURED, captured.Status)
+		}
+	})
+
+	t.Run("Capture expired hold", func(t *testing.T) {
+		userid := "testuserid"
+		h := &hold.Hold{ID: "holdid", UserID: userid, Sum: 40, Status: hold.ACTIVE, Expired: true}
+
+		mockHoldRepo.EXPECT().GetByID(gomock.Any(), userid, h.ID).Return(h, nil)
+
+		_, err := balanceUsecase.CaptureHold(context.Background(), userid, h.ID)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, hold.ErrNotActive, err)
+		}
+	})
+}
+
+func TestWithdrawPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	mockWithdrawsRepo := mocks.NewMockWithdrawsRepo(ctrl)
+	mockHoldRepo := mocks.NewMockHoldRepo(ctrl)
+	mockPoints := mocks.NewMockPoints(ctrl)
+	mockTransactor := mocktransactor.New()
+	mockLogger := mocklogger.New()
+	policy := balance.WithdrawPolicy{
+		Min:        10,
+		DailyLimit: 100,
+	}
+	balanceUsecase := usecase.New(mockBalanceRepo, mockWithdrawsRepo, mockHoldRepo, mockPoints, mockTransactor, policy, hold.TTLPolicy{}, mockLogger)
+
+	t.Run("Withdraw error below minimum", func(t *testing.T) {
+		err := balanceUsecase.Withdraw(context.Background(), "testuserid", "ordernum", 5)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, balance.ErrBelowMinimum, err)
+		}
+	})
+
+	t.Run("Withdraw error daily limit", func(t *testing.T) {
+		userid := "testuserid"
+		var withdraw float64 = 30
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{
+			Current: 500,
+		}, nil)
+
+		mockHoldRepo.EXPECT().GetActiveSum(gomock.Any(), userid).Return(float64(0), nil)
+		mockWithdrawsRepo.EXPECT().GetSumByPeriod(gomock.Any(), userid, balance.PeriodDay).Return(float64(80), nil)
+
+		err := balanceUsecase.Withdraw(context.Background(), userid, "ordernum", withdraw)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, balance.ErrDailyLimitExceeded, err)
+		}
+	})
+
+	t.Run("Withdraw error daily limit with active holds", func(t *testing.T) {
+		userid := "testuserid"
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{
+			Current: 500,
+		}, nil)
+
+		mockHoldRepo.EXPECT().GetActiveSum(gomock.Any(), userid).Return(float64(60), nil)
+		mockWithdrawsRepo.EXPECT().GetSumByPeriod(gomock.Any(), userid, balance.PeriodDay).Return(float64(20), nil)
+
+		err := balanceUsecase.Withdraw(context.Background(), userid, "ordernum", 30)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, balance.ErrDailyLimitExceeded, err)
+		}
+	})
+
+	t.Run("Capture error daily limit", func(t *testing.T) {
+		userid := "testuserid"
+		//резерв создан вчера, сегодня лимит уже выбран другими списаниями
+		h := &hold.Hold{ID: "holdid", UserID: userid, Order: "ordernum", Sum: 40, Status: hold.ACTIVE}
+
+		mockHoldRepo.EXPECT().GetByID(gomock.Any(), userid, h.ID).Return(h, nil)
+		mockHoldRepo.EXPECT().GetActiveSum(gomock.Any(), userid).Return(h.Sum, nil)
+		mockWithdrawsRepo.EXPECT().GetSumByPeriod(gomock.Any(), userid, balance.PeriodDay).Return(float64(80), nil)
+
+		_, err := balanceUsecase.CaptureHold(context.Background(), userid, h.ID)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, balance.ErrDailyLimitExceeded, err)
+		}
+	})
 }
@@ -0,0 +1,88 @@
+package campaign
+
+import (
+	"errors"
+	"time"
+)
+
+// Вид бонуса кампании
+type Kind string
+
+const (
+	//бонус пропорционален начислению за заказ: Value=2 удваивает баллы
+	MULTIPLIER Kind = "MULTIPLIER"
+	//фиксированный бонус за заказ
+	FIXED Kind = "FIXED"
+)
+
+// Промо-кампания. Бонус начисляется за заказы, загруженные в период [StartsAt, EndsAt)
+type Campaign struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Kind     Kind      `json:"kind"`
+	Value    float64   `json:"value"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	//условия участия
+	FirstOrderOnly bool      `json:"first_order_only"`
+	MinAccrual     float64   `json:"min_accrual"`
+	Disabled       bool      `json:"disabled"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Бонус по кампании за конкретный заказ
+type Bonus struct {
+	ID         string    `json:"id"`
+	CampaignID string    `json:"campaign_id"`
+	UserID     string    `json:"user_id"`
+	Order      string    `json:"order"`
+	Amount     float64   `json:"amount"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+var (
+	ErrNotFound     = errors.New("campaign not found")
+	ErrInvalidKind  = errors.New("unknown campaign kind")
+	ErrInvalidValue = errors.New("invalid campaign value")
+	ErrInvalidRange = errors.New("campaign must end after start")
+)
+
+func (c *Campaign) Validate() error {
+	switch c.Kind {
+	case MULTIPLIER:
+		if c.Value <= 1 {
+			return ErrInvalidValue
+		}
+	case FIXED:
+		if c.Value <= 0 {
+			return ErrInvalidValue
+		}
+	default:
+		return ErrInvalidKind
+	}
+
+	if c.MinAccrual < 0 {
+		return ErrInvalidValue
+	}
+
+	if !c.EndsAt.After(c.StartsAt) {
+		return ErrInvalidRange
+	}
+
+	return nil
+}
+
+// Размер бонуса за заказ с начислением accrual, 0 - заказ не подходит под условия кампании
+func (c *Campaign) Bonus(accrual float64) float64 {
+	if accrual < c.MinAccrual {
+		return 0
+	}
+
+	switch c.Kind {
+	case MULTIPLIER:
+		return accrual * (c.Value - 1)
+	case FIXED:
+		return c.Value
+	}
+	return 0
+}
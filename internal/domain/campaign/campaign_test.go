@@ -0,0 +1,40 @@
+package campaign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	start := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+
+	tests := []struct {
+		name string
+		c    Campaign
+		err  error
+	}{
+		{name: "multiplier", c: Campaign{Kind: MULTIPLIER, Value: 2, StartsAt: start, EndsAt: end}},
+		{name: "fixed", c: Campaign{Kind: FIXED, Value: 500, StartsAt: start, EndsAt: end}},
+		{name: "multiplier not above one", c: Campaign{Kind: MULTIPLIER, Value: 1, StartsAt: start, EndsAt: end}, err: ErrInvalidValue},
+		{name: "negative fixed", c: Campaign{Kind: FIXED, Value: -1, StartsAt: start, EndsAt: end}, err: ErrInvalidValue},
+		{name: "unknown kind", c: Campaign{Kind: "PERCENT", Value: 2, StartsAt: start, EndsAt: end}, err: ErrInvalidKind},
+		{name: "ends before start", c: Campaign{Kind: FIXED, Value: 1, StartsAt: end, EndsAt: start}, err: ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.c.Validate(), tt.err)
+		})
+	}
+}
+
+func TestBonus(t *testing.T) {
+	double := Campaign{Kind: MULTIPLIER, Value: 2}
+	fixed := Campaign{Kind: FIXED, Value: 500, MinAccrual: 100}
+
+	assert.Equal(t, 150.0, double.Bonus(150))
+	assert.Equal(t, 500.0, fixed.Bonus(100))
+	assert.Equal(t, 0.0, fixed.Bonus(99))
+}
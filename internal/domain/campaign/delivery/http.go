@@ -0,0 +1,109 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/campaign"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type CampaignUsecase interface {
+	Create(ctx context.Context, cmp *campaign.Campaign) (*campaign.Campaign, error)
+	GetAll(ctx context.Context) ([]campaign.Campaign, error)
+	Disable(ctx context.Context, id string) (*campaign.Campaign, error)
+}
+
+type campaignHandler struct {
+	logger logger.Logger
+	CampaignUsecase
+}
+
+type CampaignModel struct {
+	Name           string    `json:"name" validate:"required"`
+	Kind           string    `json:"kind" validate:"required"`
+	Value          float64   `json:"value" validate:"required"`
+	StartsAt       time.Time `json:"starts_at" validate:"required"`
+	EndsAt         time.Time `json:"ends_at" validate:"required"`
+	FirstOrderOnly bool      `json:"first_order_only"`
+	MinAccrual     float64   `json:"min_accrual"`
+}
+
+// Регистрирует обработчики в группе /api/admin, доступ к которой уже ограничен администраторами
+func NewCampaignHandlers(group *echo.Group, cu CampaignUsecase, logger logger.Logger) {
+	h := &campaignHandler{
+		CampaignUsecase: cu,
+		logger:          logger,
+	}
+
+	group.POST("/campaigns", h.CreateHandler)
+	group.GET("/campaigns", h.GetAllHandler)
+	group.POST("/campaigns/:id/disable", h.DisableHandler)
+}
+
+func (h *campaignHandler) CreateHandler(c echo.Context) error {
+	var m CampaignModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	created, err := h.Create(c.Request().Context(), &campaign.Campaign{
+		Name:           m.Name,
+		Kind:           campaign.Kind(m.Kind),
+		Value:          m.Value,
+		StartsAt:       m.StartsAt,
+		EndsAt:         m.EndsAt,
+		FirstOrderOnly: m.FirstOrderOnly,
+		MinAccrual:     m.MinAccrual,
+	})
+
+	if err != nil {
+		if errors.Is(err, campaign.ErrInvalidKind) ||
+			errors.Is(err, campaign.ErrInvalidValue) ||
+			errors.Is(err, campaign.ErrInvalidRange) {
+			return c.JSON(http.StatusUnprocessableEntity, httputils.Error(err.Error()))
+		}
+
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, created)
+}
+
+func (h *campaignHandler) GetAllHandler(c echo.Context) error {
+	list, err := h.GetAll(c.Request().Context())
+	if err != nil {
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if len(list) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *campaignHandler) DisableHandler(c echo.Context) error {
+	disabled, err := h.Disable(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, campaign.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, httputils.Error(err.Error()))
+		}
+
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, disabled)
+}
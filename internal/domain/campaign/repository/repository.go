@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/benderr/gophermart/internal/domain/campaign"
+	"github.com/benderr/gophermart/internal/domain/orders"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+const campaignColumns = `c.id, c.name, c.kind, c.value, c.starts_at, c.ends_at, c.first_order_only, c.min_accrual, c.disabled, c.created_at`
+
+type campaignRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *campaignRepository {
+	return &campaignRepository{db: db, log: log}
+}
+
+// Время передается как timestamptz, чтобы postgres привел его к часовому поясу сессии, как и NOW()
+func (r *campaignRepository) Create(ctx context.Context, c *campaign.Campaign) (*campaign.Campaign, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `INSERT INTO campaigns AS c (name, kind, value, starts_at, ends_at, first_order_only, min_accrual)
+	VALUES($1, $2, $3, $4::timestamptz, $5::timestamptz, $6, $7)
+	RETURNING `+campaignColumns,
+		c.Name, c.Kind, c.Value, c.StartsAt, c.EndsAt, c.FirstOrderOnly, c.MinAccrual)
+	r.log.Infoln("[CREATE CAMPAIGN]", c.Name, c.Kind, c.Value)
+	return scanCampaign(row)
+}
+
+func (r *campaignRepository) GetAll(ctx context.Context) ([]campaign.Campaign, error) {
+	rows, err := transactor.FromContext(ctx, r.db).QueryContext(ctx, `SELECT `+campaignColumns+` FROM campaigns c ORDER BY c.starts_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	return scanCampaigns(rows)
+}
+
+func (r *campaignRepository) Disable(ctx context.Context, id string) (*campaign.Campaign, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `UPDATE campaigns AS c SET disabled=true WHERE c.id=$1 RETURNING `+campaignColumns, id)
+	r.log.Infoln("[DISABLE CAMPAIGN]", id)
+	return scanCampaign(row)
+}
+
+// Активные кампании, в период которых загружен заказ и условиям которых заказ соответствует.
+// Первым считается заказ, если у пользователя нет других обработанных заказов
+func (r *campaignRepository) GetEligible(ctx context.Context, number string) ([]campaign.Campaign, error) {
+	rows, err := transactor.FromContext(ctx, r.db).QueryContext(ctx, `SELECT `+campaignColumns+` FROM campaigns c
+	JOIN orders o ON o.order_num=$1
+	WHERE NOT c.disabled AND c.starts_at <= o.uploaded_at AND c.ends_at > o.uploaded_at
+	AND (NOT c.first_order_only OR NOT EXISTS (
+		SELECT 1 FROM orders p WHERE p.user_id=o.user_id AND p.status=$2 AND p.order_num <> o.order_num))
+	ORDER BY c.created_at`, number, orders.PROCESSED)
+	if err != nil {
+		return nil, err
+	}
+	return scanCampaigns(rows)
+}
+
+// Сохраняет бонус, повторный бонус той же кампании за тот же заказ не создается (nil, nil)
+func (r *campaignRepository) CreateBonus(ctx context.Context, b *campaign.Bonus) (*campaign.Bonus, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `INSERT INTO campaign_bonuses (campaign_id, user_id, order_num, amount)
+	VALUES($1, $2, $3, $4)
+	ON CONFLICT (campaign_id, order_num) DO NOTHING
+	RETURNING id, campaign_id, user_id, order_num, amount, created_at`, b.CampaignID, b.UserID, b.Order, b.Amount)
+
+	var created campaign.Bonus
+	err := row.Scan(&created.ID, &created.CampaignID, &created.UserID, &created.Order, &created.Amount, &created.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &created, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCampaign(row scanner) (*campaign.Campaign, error) {
+	var c campaign.Campaign
+	err := row.Scan(&c.ID, &c.Name, &c.Kind, &c.Value, &c.StartsAt, &c.EndsAt, &c.FirstOrderOnly, &c.MinAccrual, &c.Disabled, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, campaign.ErrNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanCampaigns(rows *sql.Rows) ([]campaign.Campaign, error) {
+	list := make([]campaign.Campaign, 0)
+
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, *c)
+	}
+
+	err := rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
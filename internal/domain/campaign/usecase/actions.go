@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/campaign"
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type campaignUsecase struct {
+	campaignRepo CampaignRepo
+	balanceRepo  BalanceRepo
+	points       Points
+	logger       logger.Logger
+}
+
+func New(cr CampaignRepo, br BalanceRepo, pts Points, l logger.Logger) *campaignUsecase {
+	return &campaignUsecase{
+		campaignRepo: cr,
+		balanceRepo:  br,
+		points:       pts,
+		logger:       l}
+}
+
+func (c *campaignUsecase) Create(ctx context.Context, cmp *campaign.Campaign) (*campaign.Campaign, error) {
+	if err := cmp.Validate(); err != nil {
+		return nil, err
+	}
+	return c.campaignRepo.Create(ctx, cmp)
+}
+
+func (c *campaignUsecase) GetAll(ctx context.Context) ([]campaign.Campaign, error) {
+	return c.campaignRepo.GetAll(ctx)
+}
+
+func (c *campaignUsecase) Disable(ctx context.Context, id string) (*campaign.Campaign, error) {
+	return c.campaignRepo.Disable(ctx, id)
+}
+
+// Начисляет бонусы всех подходящих кампаний за обработанный заказ.
+// Вызывается в транзакции начисления за заказ, каждый бонус - отдельная партия баллов
+// с источником campaign и записью, связанной с кампанией и заказом
+func (c *campaignUsecase) ApplyBonuses(ctx context.Context, userid string, number string, accrual float64) (float64, error) {
+	list, err := c.campaignRepo.GetEligible(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, cmp := range list {
+		amount := cmp.Bonus(accrual)
+		if amount <= 0 {
+			continue
+		}
+
+		bonus, err := c.campaignRepo.CreateBonus(ctx, &campaign.Bonus{
+			CampaignID: cmp.ID,
+			UserID:     userid,
+			Order:      number,
+			Amount:     amount,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if bonus == nil {
+			continue
+		}
+
+		net, err := c.balanceRepo.Add(ctx, userid, &amount)
+		if err != nil {
+			return 0, err
+		}
+
+		err = c.points.Credit(ctx, userid, points.SourceCampaign, number, amount, net)
+		if err != nil {
+			return 0, err
+		}
+
+		c.logger.Infow("[CAMPAIGN BONUS]", "bonus", bonus)
+		total += amount
+	}
+
+	return total, nil
+}
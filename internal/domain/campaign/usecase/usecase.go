@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/campaign"
+	"github.com/benderr/gophermart/internal/domain/points"
+)
+
+type CampaignRepo interface {
+	Create(ctx context.Context, c *campaign.Campaign) (*campaign.Campaign, error)
+	GetAll(ctx context.Context) ([]campaign.Campaign, error)
+	Disable(ctx context.Context, id string) (*campaign.Campaign, error)
+	GetEligible(ctx context.Context, number string) ([]campaign.Campaign, error)
+	CreateBonus(ctx context.Context, b *campaign.Bonus) (*campaign.Bonus, error)
+}
+
+type BalanceRepo interface {
+	//возвращает сумму, зачисленную на баланс после погашения долга
+	Add(ctx context.Context, userid string, balance *float64) (float64, error)
+}
+
+type Points interface {
+	Credit(ctx context.Context, userid string, source points.Source, order string, credited float64, net float64) error
+}
@@ -0,0 +1,80 @@
+package clawback
+
+import (
+	"errors"
+	"time"
+)
+
+// Что делать, если отозванных баллов уже нет на балансе
+type Policy string
+
+const (
+	//баланс уходит в минус, последующие начисления его погашают
+	NEGATIVE Policy = "negative"
+	//списываем доступное, остаток учитываем как долг, списания запрещены до погашения долга
+	BLOCK Policy = "block"
+	//недостачу не больше порога списываем в убыток, большую учитываем как долг (как BLOCK)
+	WRITEOFF Policy = "writeoff"
+)
+
+var ErrInvalidPolicy = errors.New("invalid clawback policy")
+
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case NEGATIVE, BLOCK, WRITEOFF:
+		return p, nil
+	}
+	return "", ErrInvalidPolicy
+}
+
+// Отзыв начисления за заказ, который после обработки признан недействительным.
+// Amount = Debited + Debt + WrittenOff
+type Clawback struct {
+	ID         string    `json:"id"`
+	Order      string    `json:"order"`
+	Amount     float64   `json:"amount"`
+	Debited    float64   `json:"debited"`
+	Debt       float64   `json:"debt"`
+	WrittenOff float64   `json:"written_off"`
+	Policy     Policy    `json:"policy"`
+	CreatedAt  time.Time `json:"created_at"`
+	UserID     string    `json:"-"`
+}
+
+type Rules struct {
+	Policy            Policy
+	WriteOffThreshold float64
+}
+
+// Распределяет отзываемую сумму между балансом, долгом и списанием в убыток.
+// available - баллы, которые можно списать (без зарезервированных)
+func (r Rules) Apply(amount float64, available float64) Clawback {
+	c := Clawback{Amount: amount, Policy: r.Policy}
+
+	if r.Policy == NEGATIVE {
+		c.Debited = amount
+		return c
+	}
+
+	if available < 0 {
+		available = 0
+	}
+
+	c.Debited = amount
+	if c.Debited > available {
+		c.Debited = available
+	}
+
+	shortfall := amount - c.Debited
+	if shortfall <= 0 {
+		return c
+	}
+
+	if r.Policy == WRITEOFF && shortfall <= r.WriteOffThreshold {
+		c.WrittenOff = shortfall
+	} else {
+		c.Debt = shortfall
+	}
+
+	return c
+}
@@ -0,0 +1,68 @@
+package clawback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     Rules
+		amount    float64
+		available float64
+		want      Clawback
+	}{
+		{
+			name:   "enough points",
+			rules:  Rules{Policy: BLOCK},
+			amount: 100, available: 150,
+			want: Clawback{Amount: 100, Debited: 100, Policy: BLOCK},
+		},
+		{
+			name:   "negative balance",
+			rules:  Rules{Policy: NEGATIVE},
+			amount: 100, available: 30,
+			want: Clawback{Amount: 100, Debited: 100, Policy: NEGATIVE},
+		},
+		{
+			name:   "block with debt",
+			rules:  Rules{Policy: BLOCK},
+			amount: 100, available: 30,
+			want: Clawback{Amount: 100, Debited: 30, Debt: 70, Policy: BLOCK},
+		},
+		{
+			name:   "write off below threshold",
+			rules:  Rules{Policy: WRITEOFF, WriteOffThreshold: 50},
+			amount: 100, available: 60,
+			want: Clawback{Amount: 100, Debited: 60, WrittenOff: 40, Policy: WRITEOFF},
+		},
+		{
+			name:   "write off above threshold becomes debt",
+			rules:  Rules{Policy: WRITEOFF, WriteOffThreshold: 50},
+			amount: 100, available: 0,
+			want: Clawback{Amount: 100, Debt: 100, Policy: WRITEOFF},
+		},
+		{
+			name:   "negative available",
+			rules:  Rules{Policy: BLOCK},
+			amount: 10, available: -5,
+			want: Clawback{Amount: 10, Debt: 10, Policy: BLOCK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rules.Apply(tt.amount, tt.available))
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("writeoff")
+	assert.NoError(t, err)
+	assert.Equal(t, WRITEOFF, p)
+
+	_, err = ParsePolicy("forgive")
+	assert.ErrorIs(t, err, ErrInvalidPolicy)
+}
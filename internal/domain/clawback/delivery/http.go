@@ -0,0 +1,57 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/clawback"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type ClawbackUsecase interface {
+	GetByUser(ctx context.Context, userid string) ([]clawback.Clawback, error)
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type clawbackHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	ClawbackUsecase
+}
+
+func NewClawbackHandlers(group *echo.Group, cu ClawbackUsecase, session SessionManager, logger logger.Logger) {
+	h := &clawbackHandler{
+		ClawbackUsecase: cu,
+		session:         session,
+		logger:          logger,
+	}
+
+	g := group.Group("/api/user")
+
+	g.GET("/balance/clawbacks", h.GetClawbacksHandler)
+}
+
+func (h *clawbackHandler) GetClawbacksHandler(c echo.Context) error {
+	userid, err := h.session.GetUserID(c)
+	if err != nil {
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	list, err := h.GetByUser(c.Request().Context(), userid)
+	if err != nil {
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if len(list) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
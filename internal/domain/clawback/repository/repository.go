@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/benderr/gophermart/internal/domain/clawback"
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type clawbackRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *clawbackRepository {
+	return &clawbackRepository{db: db, log: log}
+}
+
+// Сумма, начисленная за заказ и еще не отозванная: начисления партий заказа и бонусов кампаний
+// до погашения долга за вычетом прошлых отзывов, иначе заказ, повторно прошедший PROCESSED и INVALID,
+// отзывался бы дважды. Партии без credited созданы до его появления и хранят сумму после погашения долга.
+// Для заказов, начисленных до появления партий, берем accrual заказа
+func (c *clawbackRepository) GetCredited(ctx context.Context, userid string, order string) (float64, error) {
+	row := transactor.FromContext(ctx, c.db).QueryRowContext(ctx, `SELECT COALESCE(
+		(SELECT SUM(COALESCE(credited, amount)) FROM balance_lots WHERE user_id=$1 AND order_num=$2 AND source IN ($3, $4)),
+		(SELECT accrual FROM orders WHERE user_id=$1 AND order_num=$2),
+		0) - (SELECT COALESCE(SUM(amount), 0) FROM clawbacks WHERE user_id=$1 AND order_num=$2)`, userid, order, points.SourceOrder, points.SourceCampaign)
+
+	var credited float64
+	err := row.Scan(&credited)
+	return credited, err
+}
+
+func (c *clawbackRepository) Create(ctx context.Context, cb *clawback.Clawback) error {
+	_, err := transactor.FromContext(ctx, c.db).ExecContext(ctx, `INSERT INTO clawbacks (user_id, order_num, amount, debited, debt, written_off, policy)
+	VALUES($1, $2, $3, $4, $5, $6, $7)`, cb.UserID, cb.Order, cb.Amount, cb.Debited, cb.Debt, cb.WrittenOff, cb.Policy)
+	c.log.Infow("[CLAWBACK]", "clawback", cb)
+	return err
+}
+
+func (c *clawbackRepository) GetByUser(ctx context.Context, userid string) ([]clawback.Clawback, error) {
+	list := make([]clawback.Clawback, 0)
+
+	rows, err := transactor.FromContext(ctx, c.db).QueryContext(ctx, `SELECT id, user_id, order_num, amount, debited, debt, written_off, policy, created_at
+	FROM clawbacks WHERE user_id=$1 ORDER BY created_at DESC`, userid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var cb clawback.Clawback
+		err = rows.Scan(&cb.ID, &cb.UserID, &cb.Order, &cb.Amount, &cb.Debited, &cb.Debt, &cb.WrittenOff, &cb.Policy, &cb.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, cb)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/clawback"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type clawbackUsecase struct {
+	clawbackRepo ClawbackRepo
+	balanceRepo  BalanceRepo
+	points       Points
+	rules        clawback.Rules
+	logger       logger.Logger
+}
+
+func New(cr ClawbackRepo, br BalanceRepo, pts Points, r clawback.Rules, l logger.Logger) *clawbackUsecase {
+	return &clawbackUsecase{
+		clawbackRepo: cr,
+		balanceRepo:  br,
+		points:       pts,
+		rules:        r,
+		logger:       l}
+}
+
+// Отзывает начисление за заказ, признанный недействительным после обработки.
+// Вызывается в транзакции смены статуса заказа. Реферальные бонусы не отзываются
+func (c *clawbackUsecase) Reverse(ctx context.Context, userid string, order string) (*clawback.Clawback, error) {
+	err := c.balanceRepo.Lock(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	credited, err := c.clawbackRepo.GetCredited(ctx, userid, order)
+	if err != nil {
+		return nil, err
+	}
+	if credited <= 0 {
+		return nil, nil
+	}
+
+	bal, err := c.balanceRepo.GetBalanceByUser(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := c.rules.Apply(credited, bal.Spendable())
+	cb.UserID = userid
+	cb.Order = order
+
+	err = c.clawbackRepo.Create(ctx, &cb)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.balanceRepo.Clawback(ctx, userid, cb.Debited, cb.Debt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.points.ConsumeOrder(ctx, userid, order, cb.Debited)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cb, nil
+}
+
+func (c *clawbackUsecase) GetByUser(ctx context.Context, userid string) ([]clawback.Clawback, error) {
+	return c.clawbackRepo.GetByUser(ctx, userid)
+}
@@ -0,0 +1,188 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/clawback/usecase (interfaces: ClawbackRepo,BalanceRepo,Points)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/clawback/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/clawback/usecase ClawbackRepo,BalanceRepo,Points
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	balance "github.com/benderr/gophermart/internal/domain/balance"
+	clawback "github.com/benderr/gophermart/internal/domain/clawback"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClawbackRepo is a mock of ClawbackRepo interface.
+type MockClawbackRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockClawbackRepoMockRecorder
+}
+
+// MockClawbackRepoMockRecorder is the mock recorder for MockClawbackRepo.
+type MockClawbackRepoMockRecorder struct {
+	mock *MockClawbackRepo
+}
+
+// NewMockClawbackRepo creates a new mock instance.
+func NewMockClawbackRepo(ctrl *gomock.Controller) *MockClawbackRepo {
+	mock := &MockClawbackRepo{ctrl: ctrl}
+	mock.recorder = &MockClawbackRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClawbackRepo) EXPECT() *MockClawbackRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockClawbackRepo) Create(arg0 context.Context, arg1 *clawback.Clawback) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockClawbackRepoMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockClawbackRepo)(nil).Create), arg0, arg1)
+}
+
+// GetByUser mocks base method.
+func (m *MockClawbackRepo) GetByUser(arg0 context.Context, arg1 string) ([]clawback.Clawback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", arg0, arg1)
+	ret0, _ := ret[0].([]clawback.Clawback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockClawbackRepoMockRecorder) GetByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockClawbackRepo)(nil).GetByUser), arg0, arg1)
+}
+
+// GetCredited mocks base method.
+func (m *MockClawbackRepo) GetCredited(arg0 context.Context, arg1, arg2 string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredited", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCredited indicates an expected call of GetCredited.
+func (mr *MockClawbackRepoMockRecorder) GetCredited(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredited", reflect.TypeOf((*MockClawbackRepo)(nil).GetCredited), arg0, arg1, arg2)
+}
+
+// MockBalanceRepo is a mock of BalanceRepo interface.
+type MockBalanceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceRepoMockRecorder
+}
+
+// MockBalanceRepoMockRecorder is the mock recorder for MockBalanceRepo.
+type MockBalanceRepoMockRecorder struct {
+	mock *MockBalanceRepo
+}
+
+// NewMockBalanceRepo creates a new mock instance.
+func NewMockBalanceRepo(ctrl *gomock.Controller) *MockBalanceRepo {
+	mock := &MockBalanceRepo{ctrl: ctrl}
+	mock.recorder = &MockBalanceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceRepo) EXPECT() *MockBalanceRepoMockRecorder {
+	return m.recorder
+}
+
+// Clawback mocks base method.
+func (m *MockBalanceRepo) Clawback(arg0 context.Context, arg1 string, arg2, arg3 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clawback", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clawback indicates an expected call of Clawback.
+func (mr *MockBalanceRepoMockRecorder) Clawback(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clawback", reflect.TypeOf((*MockBalanceRepo)(nil).Clawback), arg0, arg1, arg2, arg3)
+}
+
+// GetBalanceByUser mocks base method.
+func (m *MockBalanceRepo) GetBalanceByUser(arg0 context.Context, arg1 string) (*balance.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceByUser", arg0, arg1)
+	ret0, _ := ret[0].(*balance.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceByUser indicates an expected call of GetBalanceByUser.
+func (mr *MockBalanceRepoMockRecorder) GetBalanceByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceByUser", reflect.TypeOf((*MockBalanceRepo)(nil).GetBalanceByUser), arg0, arg1)
+}
+
+// Lock mocks base method.
+func (m *MockBalanceRepo) Lock(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockBalanceRepoMockRecorder) Lock(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockBalanceRepo)(nil).Lock), arg0, arg1)
+}
+
+// MockPoints is a mock of Points interface.
+type MockPoints struct {
+	ctrl     *gomock.Controller
+	recorder *MockPointsMockRecorder
+}
+
+// MockPointsMockRecorder is the mock recorder for MockPoints.
+type MockPointsMockRecorder struct {
+	mock *MockPoints
+}
+
+// NewMockPoints creates a new mock instance.
+func NewMockPoints(ctrl *gomock.Controller) *MockPoints {
+	mock := &MockPoints{ctrl: ctrl}
+	mock.recorder = &MockPointsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPoints) EXPECT() *MockPointsMockRecorder {
+	return m.recorder
+}
+
+// ConsumeOrder mocks base method.
+func (m *MockPoints) ConsumeOrder(arg0 context.Context, arg1, arg2 string, arg3 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOrder", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeOrder indicates an expected call of ConsumeOrder.
+func (mr *MockPointsMockRecorder) ConsumeOrder(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOrder", reflect.TypeOf((*MockPoints)(nil).ConsumeOrder), arg0, arg1, arg2, arg3)
+}
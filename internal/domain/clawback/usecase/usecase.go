@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/clawback"
+)
+
+type ClawbackRepo interface {
+	GetCredited(ctx context.Context, userid string, order string) (float64, error)
+	Create(ctx context.Context, cb *clawback.Clawback) error
+	GetByUser(ctx context.Context, userid string) ([]clawback.Clawback, error)
+}
+
+type BalanceRepo interface {
+	Lock(ctx context.Context, userid string) error
+	GetBalanceByUser(ctx context.Context, userid string) (*balance.Balance, error)
+	Clawback(ctx context.Context, userid string, debited float64, debt float64) error
+}
+
+type Points interface {
+	ConsumeOrder(ctx context.Context, userid string, order string, amount float64) error
+}
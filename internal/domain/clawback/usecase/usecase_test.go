@@ -0,0 +1,54 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/clawback"
+	"github.com/benderr/gophermart/internal/domain/clawback/usecase"
+	"github.com/benderr/gophermart/internal/domain/clawback/usecase/mocks"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReverse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClawbackRepo := mocks.NewMockClawbackRepo(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	mockPoints := mocks.NewMockPoints(ctrl)
+	clawbackUsecase := usecase.New(mockClawbackRepo, mockBalanceRepo, mockPoints, clawback.Rules{Policy: clawback.BLOCK}, mocklogger.New())
+
+	t.Run("Accrual repays debt then invalid order", func(t *testing.T) {
+		//из начисления 100 долг 70 погашен, на баланс пришло 30. Отзывается все начисление:
+		//30 списываются с баланса, погашенный долг возвращается
+		mockBalanceRepo.EXPECT().Lock(gomock.Any(), "user").Return(nil)
+		mockClawbackRepo.EXPECT().GetCredited(gomock.Any(), "user", "123").Return(100.0, nil)
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), "user").Return(&balance.Balance{Current: 30}, nil)
+		mockClawbackRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		mockBalanceRepo.EXPECT().Clawback(gomock.Any(), "user", 30.0, 70.0).Return(nil)
+		mockPoints.EXPECT().ConsumeOrder(gomock.Any(), "user", "123", 30.0).Return(nil)
+
+		cb, err := clawbackUsecase.Reverse(context.Background(), "user", "123")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, 100.0, cb.Amount)
+			assert.Equal(t, 30.0, cb.Debited)
+			assert.Equal(t, 70.0, cb.Debt)
+		}
+	})
+
+	t.Run("Order already reversed", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Lock(gomock.Any(), "user").Return(nil)
+		mockClawbackRepo.EXPECT().GetCredited(gomock.Any(), "user", "123").Return(0.0, nil)
+
+		cb, err := clawbackUsecase.Reverse(context.Background(), "user", "123")
+
+		if assert.NoError(t, err) {
+			assert.Nil(t, cb)
+		}
+	})
+}
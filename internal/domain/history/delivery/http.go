@@ -0,0 +1,58 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/history"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type HistoryUsecase interface {
+	GetHistoryByUser(ctx context.Context, userid string) ([]history.Entry, error)
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type historyHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	HistoryUsecase
+}
+
+func NewHistoryHandlers(group *echo.Group, hu HistoryUsecase, session SessionManager, logger logger.Logger) {
+	h := &historyHandler{
+		HistoryUsecase: hu,
+		session:        session,
+		logger:         logger,
+	}
+
+	g := group.Group("/api/user")
+
+	g.GET("/history", h.GetHistoryHandler)
+}
+
+func (h *historyHandler) GetHistoryHandler(c echo.Context) error {
+	userid, err := h.session.GetUserID(c)
+	if err != nil {
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	list, err := h.GetHistoryByUser(c.Request().Context(), userid)
+
+	if err != nil {
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if len(list) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
@@ -0,0 +1,24 @@
+package history
+
+import "time"
+
+// Тип операции. Для начислений совпадает с источником партии баллов (points.Source)
+type Type string
+
+const (
+	Withdrawal  Type = "withdrawal"
+	TransferIn  Type = "transfer_in"
+	TransferOut Type = "transfer_out"
+	Expiration  Type = "expiration"
+	Adjustment  Type = "adjustment"
+	Clawback    Type = "clawback"
+)
+
+// Операция по счету пользователя. Списания имеют отрицательную сумму
+type Entry struct {
+	Type         Type      `json:"type"`
+	Sum          float64   `json:"sum"`
+	Order        string    `json:"order,omitempty"`
+	Counterparty string    `json:"counterparty,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
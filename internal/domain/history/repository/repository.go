@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/benderr/gophermart/internal/domain/history"
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type historyRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *historyRepository {
+	return &historyRepository{db: db, log: log}
+}
+
+// Собирает историю операций пользователя из таблиц начислений, списаний, переводов, сгораний, корректировок и отзывов начислений.
+// Входящие переводы берем из transfers, чтобы знать отправителя, а не из партий баллов
+func (h *historyRepository) GetByUser(ctx context.Context, userid string) ([]history.Entry, error) {
+	list := make([]history.Entry, 0)
+
+	rows, err := transactor.FromContext(ctx, h.db).QueryContext(ctx, `SELECT type, sum, order_num, counterparty, created_at FROM (
+		SELECT l.source AS type, l.amount AS sum, COALESCE(l.order_num, '') AS order_num, '' AS counterparty, l.created_at
+		FROM balance_lots l WHERE l.user_id=$1 AND l.source <> $2 AND l.amount > 0
+		UNION ALL
+		SELECT $3, -w.sum, w.order_num, '', w.processed_at
+		FROM withdrawals w WHERE w.user_id=$1
+		UNION ALL
+		SELECT $4, -t.amount, '', u.login, t.created_at
+		FROM transfers t JOIN users u ON u.id=t.to_user_id WHERE t.from_user_id=$1
+		UNION ALL
+		SELECT $5, t.amount, '', u.login, t.created_at
+		FROM transfers t JOIN users u ON u.id=t.from_user_id WHERE t.to_user_id=$1
+		UNION ALL
+		SELECT $6, -e.amount, '', '', e.expired_at
+		FROM balance_expirations e WHERE e.user_id=$1
+		UNION ALL
+		SELECT $7, a.amount, '', '', a.created_at
+		FROM balance_adjustments a WHERE a.user_id=$1 AND a.amount <> 0
+		UNION ALL
+		SELECT $8, -(c.amount - c.written_off), c.order_num, '', c.created_at
+		FROM clawbacks c WHERE c.user_id=$1
+	) h ORDER BY created_at DESC`,
+		userid, points.SourceTransfer, history.Withdrawal, history.TransferOut, history.TransferIn, history.Expiration, history.Adjustment, history.Clawback)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var e history.Entry
+		err = rows.Scan(&e.Type, &e.Sum, &e.Order, &e.Counterparty, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, e)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
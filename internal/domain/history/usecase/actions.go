@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/history"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type historyUsecase struct {
+	hr     HistoryRepo
+	logger logger.Logger
+}
+
+func New(hr HistoryRepo, l logger.Logger) *historyUsecase {
+	return &historyUsecase{
+		hr:     hr,
+		logger: l}
+}
+
+func (h *historyUsecase) GetHistoryByUser(ctx context.Context, userid string) ([]history.Entry, error) {
+	return h.hr.GetByUser(ctx, userid)
+}
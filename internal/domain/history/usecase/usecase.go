@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/history"
+)
+
+type HistoryRepo interface {
+	GetByUser(ctx context.Context, userid string) ([]history.Entry, error)
+}
@@ -0,0 +1,58 @@
+package hold
+
+import (
+	"errors"
+	"time"
+)
+
+type Status string
+
+const (
+	ACTIVE   Status = "ACTIVE"
+	CAPTURED Status = "CAPTURED"
+	RELEASED Status = "RELEASED"
+	EXPIRED  Status = "EXPIRED"
+)
+
+// Резерв баллов под оплату заказа. Пока резерв активен, сумма недоступна для списания
+type Hold struct {
+	ID         string     `json:"id"`
+	Order      string     `json:"order"`
+	Sum        float64    `json:"sum"`
+	Status     Status     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	//срок резерва истек, но фоновая задача его еще не освободила
+	Expired bool   `json:"-"`
+	UserID  string `json:"-"`
+}
+
+// Срок жизни резерва по умолчанию и максимально допустимый
+type TTLPolicy struct {
+	Default time.Duration
+	Max     time.Duration
+}
+
+func (p TTLPolicy) Resolve(requested time.Duration) (time.Duration, error) {
+	if requested < 0 {
+		return 0, ErrInvalidTTL
+	}
+
+	if requested == 0 {
+		return p.Default, nil
+	}
+
+	if p.Max > 0 && requested > p.Max {
+		return 0, ErrInvalidTTL
+	}
+
+	return requested, nil
+}
+
+var (
+	ErrNotFound = errors.New("hold not found")
+	//резерв уже подтвержден, отменен или истек
+	ErrNotActive  = errors.New("hold is not active")
+	ErrInvalidTTL = errors.New("invalid hold ttl")
+)
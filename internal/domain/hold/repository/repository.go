@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/hold"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const holdColumns = `id, user_id, order_num, amount, status, created_at, expires_at, resolved_at, expires_at <= NOW()`
+
+type holdRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *holdRepository {
+	return &holdRepository{db: db, log: log}
+}
+
+func (h *holdRepository) Create(ctx context.Context, userid string, order string, sum float64, ttl time.Duration) (*hold.Hold, error) {
+	row := transactor.FromContext(ctx, h.db).QueryRowContext(ctx, `INSERT INTO balance_holds (user_id, order_num, amount, status, expires_at)
+	VALUES($1, $2, $3, $4, NOW() + $5 * interval '1 second')
+	RETURNING `+holdColumns, userid, order, sum, hold.ACTIVE, ttl.Seconds())
+	h.log.Infoln("[CREATE HOLD]", userid, order, sum)
+	return scanHold(row)
+}
+
+// Резерв пользователя с блокировкой строки, id не в формате UUID - ErrNotFound
+func (h *holdRepository) GetByID(ctx context.Context, userid string, id string) (*hold.Hold, error) {
+	row := transactor.FromContext(ctx, h.db).QueryRowContext(ctx, `SELECT `+holdColumns+` FROM balance_holds WHERE id=$1 AND user_id=$2 FOR UPDATE`, id, userid)
+	v, err := scanHold(row)
+	if err != nil {
+		var perr *pgconn.PgError
+		if errors.As(err, &perr) && perr.Code == pgerrcode.InvalidTextRepresentation {
+			return nil, hold.ErrNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// Сумма активных непросроченных резервов пользователя
+func (h *holdRepository) GetActiveSum(ctx context.Context, userid string) (float64, error) {
+	row := transactor.FromContext(ctx, h.db).QueryRowContext(ctx, `SELECT COALESCE(SUM(amount), 0) FROM balance_holds
+	WHERE user_id=$1 AND status=$2 AND expires_at > NOW()`, userid, hold.ACTIVE)
+	var sum float64
+	err := row.Scan(&sum)
+	return sum, err
+}
+
+// Активные резервы с истекшим сроком, строки, заблокированные другим обработчиком, пропускаем
+func (h *holdRepository) GetExpired(ctx context.Context, limit int) ([]hold.Hold, error) {
+	list := make([]hold.Hold, 0)
+
+	rows, err := transactor.FromContext(ctx, h.db).QueryContext(ctx, `SELECT `+holdColumns+` FROM balance_holds
+	WHERE status=$1 AND expires_at <= NOW()
+	ORDER BY expires_at ASC
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED`, hold.ACTIVE, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		v, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, *v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (h *holdRepository) UpdateStatus(ctx context.Context, id string, status hold.Status) (*hold.Hold, error) {
+	row := transactor.FromContext(ctx, h.db).QueryRowContext(ctx, `UPDATE balance_holds SET status=$1, resolved_at=NOW() WHERE id=$2 RETURNING `+holdColumns, status, id)
+	h.log.Infoln("[UPDATE HOLD]", id, status)
+	return scanHold(row)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanHold(row scanner) (*hold.Hold, error) {
+	var v hold.Hold
+	var resolvedAt sql.NullTime
+	err := row.Scan(&v.ID, &v.UserID, &v.Order, &v.Sum, &v.Status, &v.CreatedAt, &v.ExpiresAt, &resolvedAt, &v.Expired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, hold.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	if resolvedAt.Valid {
+		v.ResolvedAt = &resolvedAt.Time
+	}
+
+	return &v, nil
+}
@@ -0,0 +1,90 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type LockoutUsecase interface {
+	GetBlocked(ctx context.Context) ([]lockout.Attempt, error)
+	Unlock(ctx context.Context, actor string, key string) error
+	UnlockUser(ctx context.Context, actor string, userid string) error
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type lockoutHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	LockoutUsecase
+}
+
+// Регистрирует обработчики в группе /api/admin, доступ к которой уже ограничен администраторами
+func NewLockoutHandlers(group *echo.Group, lu LockoutUsecase, session SessionManager, logger logger.Logger) {
+	h := &lockoutHandler{
+		LockoutUsecase: lu,
+		session:        session,
+		logger:         logger,
+	}
+
+	group.GET("/lockouts", h.GetBlockedHandler)
+	group.DELETE("/lockouts/:key", h.UnlockHandler)
+	group.POST("/users/:id/unlock", h.UnlockUserHandler)
+}
+
+func (l *lockoutHandler) GetBlockedHandler(c echo.Context) error {
+	list, err := l.GetBlocked(c.Request().Context())
+	if err != nil {
+		l.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if len(list) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// Снимает блокировку логина или IP по ключу вида login:<логин> или ip:<адрес>
+func (l *lockoutHandler) UnlockHandler(c echo.Context) error {
+	actor, err := l.session.GetUserID(c)
+	if err != nil {
+		l.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if err := l.Unlock(c.Request().Context(), actor, c.Param("key")); err != nil {
+		l.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, httputils.Ok())
+}
+
+func (l *lockoutHandler) UnlockUserHandler(c echo.Context) error {
+	actor, err := l.session.GetUserID(c)
+	if err != nil {
+		l.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if err := l.UnlockUser(c.Request().Context(), actor, c.Param("id")); err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, httputils.Error(err.Error()))
+		}
+		l.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, httputils.Ok())
+}
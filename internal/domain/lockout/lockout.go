@@ -0,0 +1,80 @@
+package lockout
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Ограничение неудачных попыток входа для одного ключа (логин или IP)
+type Policy struct {
+	//неудачных попыток без задержки
+	FreeAttempts int
+	//задержка после первой платной попытки, удваивается с каждой следующей, но не больше MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	//после MaxFailures неудач ключ блокируется на LockDuration, 0 - без блокировки
+	MaxFailures  int
+	LockDuration time.Duration
+	//неудачи старше Window забываются, 0 - хранятся до успешного входа
+	Window time.Duration
+}
+
+// Сколько ждать до следующей попытки после failures неудач подряд
+func (p Policy) Delay(failures int) time.Duration {
+	if p.Locks(failures) {
+		return p.LockDuration
+	}
+
+	if failures <= p.FreeAttempts || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	d := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return d
+}
+
+func (p Policy) Locks(failures int) bool {
+	return p.MaxFailures > 0 && failures >= p.MaxFailures
+}
+
+// Заблокированный ключ
+type Attempt struct {
+	Key          string    `json:"key"`
+	Failures     int       `json:"failures"`
+	LastFailure  time.Time `json:"last_failure_at"`
+	BlockedUntil time.Time `json:"blocked_until"`
+}
+
+var ErrLocked = errors.New("too many login attempts")
+
+// Попытка входа отклонена до истечения задержки
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return ErrLocked.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+func LoginKey(login string) string {
+	return "login:" + strings.ToLower(login)
+}
+
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
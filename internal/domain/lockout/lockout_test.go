@@ -0,0 +1,40 @@
+package lockout_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelay(t *testing.T) {
+	policy := lockout.Policy{
+		FreeAttempts: 3,
+		BaseDelay:    time.Second,
+		MaxDelay:     10 * time.Second,
+		MaxFailures:  10,
+		LockDuration: 15 * time.Minute,
+	}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 0},
+		{failures: 3, want: 0},
+		{failures: 4, want: time.Second},
+		{failures: 5, want: 2 * time.Second},
+		{failures: 7, want: 8 * time.Second},
+		{failures: 8, want: 10 * time.Second},
+		{failures: 9, want: 10 * time.Second},
+		{failures: 10, want: 15 * time.Minute},
+		{failures: 40, want: 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, policy.Delay(tt.failures), "failures %d", tt.failures)
+	}
+
+	assert.False(t, lockout.Policy{}.Locks(100), "lock disabled")
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type lockoutRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *lockoutRepository {
+	return &lockoutRepository{db: db, log: log}
+}
+
+// Заранее засчитывает попытку как неудачную и блокирует строку ключа до конца транзакции.
+// Возвращает число неудач подряд с учетом этой попытки и сколько осталось ждать до снятия
+// уже действующей блокировки. Неудачи старше window забываются
+func (l *lockoutRepository) Reserve(ctx context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	row := transactor.FromContext(ctx, l.db).QueryRowContext(ctx, `INSERT INTO login_attempts (key, failures, last_failure_at)
+	VALUES($1, 1, NOW())
+	ON CONFLICT (key) DO UPDATE SET
+		failures = CASE WHEN $2 > 0 AND login_attempts.last_failure_at <= NOW() - $2 * interval '1 second'
+			THEN 1 ELSE login_attempts.failures + 1 END,
+		last_failure_at = NOW()
+	RETURNING failures, COALESCE(GREATEST(EXTRACT(EPOCH FROM blocked_until - NOW()), 0), 0)::float8`, key, window.Seconds())
+
+	var failures int
+	var seconds float64
+	if err := row.Scan(&failures, &seconds); err != nil {
+		return 0, 0, err
+	}
+	return failures, time.Duration(seconds * float64(time.Second)), nil
+}
+
+// Отменяет засчитанную заранее попытку
+func (l *lockoutRepository) Release(ctx context.Context, key string) error {
+	_, err := transactor.FromContext(ctx, l.db).ExecContext(ctx, `UPDATE login_attempts SET failures = GREATEST(failures - 1, 0) WHERE key=$1`, key)
+	return err
+}
+
+func (l *lockoutRepository) Block(ctx context.Context, key string, d time.Duration) error {
+	_, err := transactor.FromContext(ctx, l.db).ExecContext(ctx, `UPDATE login_attempts SET blocked_until = NOW() + $2 * interval '1 second' WHERE key=$1`, key, d.Seconds())
+	return err
+}
+
+func (l *lockoutRepository) Reset(ctx context.Context, key string) error {
+	_, err := transactor.FromContext(ctx, l.db).ExecContext(ctx, `DELETE FROM login_attempts WHERE key=$1`, key)
+	return err
+}
+
+func (l *lockoutRepository) GetBlocked(ctx context.Context) ([]lockout.Attempt, error) {
+	list := make([]lockout.Attempt, 0)
+
+	rows, err := transactor.FromContext(ctx, l.db).QueryContext(ctx, `SELECT key, failures, last_failure_at, blocked_until
+	FROM login_attempts
+	WHERE blocked_until > NOW()
+	ORDER BY blocked_until DESC`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var v lockout.Attempt
+		if err := rows.Scan(&v.Key, &v.Failures, &v.LastFailure, &v.BlockedUntil); err != nil {
+			return nil, err
+		}
+
+		list = append(list, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type lockoutUsecase struct {
+	lockoutRepo LockoutRepo
+	userRepo    UserRepo
+	transactor  Transactor
+	loginPolicy lockout.Policy
+	ipPolicy    lockout.Policy
+	logger      logger.Logger
+}
+
+// За одним IP может стоять много пользователей, поэтому для IP обычно задается более мягкая политика
+func New(lr LockoutRepo, ur UserRepo, t Transactor, loginPolicy, ipPolicy lockout.Policy, l logger.Logger) *lockoutUsecase {
+	return &lockoutUsecase{
+		lockoutRepo: lr,
+		userRepo:    ur,
+		transactor:  t,
+		loginPolicy: loginPolicy,
+		ipPolicy:    ipPolicy,
+		logger:      l,
+	}
+}
+
+// Вызывается до проверки пароля: попытка сразу засчитывается как неудачная, и задержка
+// для следующей попытки выставляется в той же транзакции. Строки ключей заблокированы,
+// пока транзакция не завершится, поэтому параллельные попытки не проходят мимо счетчика.
+// Если действует блокировка, транзакция откатывается и попытка не засчитывается
+func (l *lockoutUsecase) Reserve(ctx context.Context, login, ip string) error {
+	return l.transactor.Within(ctx, func(ctx context.Context) error {
+		if err := l.reserve(ctx, lockout.LoginKey(login), l.loginPolicy); err != nil {
+			return err
+		}
+		return l.reserve(ctx, lockout.IPKey(ip), l.ipPolicy)
+	})
+}
+
+// Успешный вход сбрасывает счетчик логина. Счетчик IP не сбрасываем, иначе перебор чужих логинов
+// можно перемежать входом в свой аккаунт, а только отменяем засчитанную попытку
+func (l *lockoutUsecase) Reset(ctx context.Context, login, ip string) error {
+	if err := l.lockoutRepo.Reset(ctx, lockout.LoginKey(login)); err != nil {
+		return err
+	}
+	return l.lockoutRepo.Release(ctx, lockout.IPKey(ip))
+}
+
+// Попытка не состоялась по внутренней причине (ошибка базы, переполнен пул хеширования)
+func (l *lockoutUsecase) Release(ctx context.Context, login, ip string) error {
+	if err := l.lockoutRepo.Release(ctx, lockout.LoginKey(login)); err != nil {
+		return err
+	}
+	return l.lockoutRepo.Release(ctx, lockout.IPKey(ip))
+}
+
+func (l *lockoutUsecase) Unlock(ctx context.Context, actor string, key string) error {
+	if err := l.lockoutRepo.Reset(ctx, key); err != nil {
+		return err
+	}
+
+	l.logger.Infow("[SECURITY] lockout removed", "key", key, "actor", actor)
+	return nil
+}
+
+func (l *lockoutUsecase) UnlockUser(ctx context.Context, actor string, userid string) error {
+	usr, err := l.userRepo.GetUserByID(ctx, userid)
+	if err != nil {
+		return err
+	}
+
+	return l.Unlock(ctx, actor, lockout.LoginKey(usr.Login))
+}
+
+func (l *lockoutUsecase) GetBlocked(ctx context.Context) ([]lockout.Attempt, error) {
+	return l.lockoutRepo.GetBlocked(ctx)
+}
+
+func (l *lockoutUsecase) reserve(ctx context.Context, key string, policy lockout.Policy) error {
+	failures, retry, err := l.lockoutRepo.Reserve(ctx, key, policy.Window)
+	if err != nil {
+		return err
+	}
+
+	if retry > 0 {
+		l.logger.Infow("[SECURITY] login rejected", "key", key, "retry_after", retry)
+		return &lockout.LockedError{RetryAfter: retry}
+	}
+
+	delay := policy.Delay(failures)
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := l.lockoutRepo.Block(ctx, key, delay); err != nil {
+		return err
+	}
+
+	if policy.Locks(failures) {
+		l.logger.Infow("[SECURITY] login locked", "key", key, "failures", failures, "duration", delay)
+	}
+
+	return nil
+}
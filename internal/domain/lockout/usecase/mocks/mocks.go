@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/lockout/usecase (interfaces: LockoutRepo,UserRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/lockout/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/lockout/usecase LockoutRepo,UserRepo
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	lockout "github.com/benderr/gophermart/internal/domain/lockout"
+	user "github.com/benderr/gophermart/internal/domain/user"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLockoutRepo is a mock of LockoutRepo interface.
+type MockLockoutRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockoutRepoMockRecorder
+}
+
+// MockLockoutRepoMockRecorder is the mock recorder for MockLockoutRepo.
+type MockLockoutRepoMockRecorder struct {
+	mock *MockLockoutRepo
+}
+
+// NewMockLockoutRepo creates a new mock instance.
+func NewMockLockoutRepo(ctrl *gomock.Controller) *MockLockoutRepo {
+	mock := &MockLockoutRepo{ctrl: ctrl}
+	mock.recorder = &MockLockoutRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockoutRepo) EXPECT() *MockLockoutRepoMockRecorder {
+	return m.recorder
+}
+
+// Block mocks base method.
+func (m *MockLockoutRepo) Block(arg0 context.Context, arg1 string, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockLockoutRepoMockRecorder) Block(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockLockoutRepo)(nil).Block), arg0, arg1, arg2)
+}
+
+// GetBlocked mocks base method.
+func (m *MockLockoutRepo) GetBlocked(arg0 context.Context) ([]lockout.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocked", arg0)
+	ret0, _ := ret[0].([]lockout.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocked indicates an expected call of GetBlocked.
+func (mr *MockLockoutRepoMockRecorder) GetBlocked(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocked", reflect.TypeOf((*MockLockoutRepo)(nil).GetBlocked), arg0)
+}
+
+// Release mocks base method.
+func (m *MockLockoutRepo) Release(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockLockoutRepoMockRecorder) Release(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLockoutRepo)(nil).Release), arg0, arg1)
+}
+
+// Reserve mocks base method.
+func (m *MockLockoutRepo) Reserve(arg0 context.Context, arg1 string, arg2 time.Duration) (int, time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(time.Duration)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockLockoutRepoMockRecorder) Reserve(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockLockoutRepo)(nil).Reserve), arg0, arg1, arg2)
+}
+
+// Reset mocks base method.
+func (m *MockLockoutRepo) Reset(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLockoutRepoMockRecorder) Reset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLockoutRepo)(nil).Reset), arg0, arg1)
+}
+
+// MockUserRepo is a mock of UserRepo interface.
+type MockUserRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepoMockRecorder
+}
+
+// MockUserRepoMockRecorder is the mock recorder for MockUserRepo.
+type MockUserRepoMockRecorder struct {
+	mock *MockUserRepo
+}
+
+// NewMockUserRepo creates a new mock instance.
+func NewMockUserRepo(ctrl *gomock.Controller) *MockUserRepo {
+	mock := &MockUserRepo{ctrl: ctrl}
+	mock.recorder = &MockUserRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
+	return m.recorder
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserRepo) GetUserByID(arg0 context.Context, arg1 string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepoMockRecorder) GetUserByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepo)(nil).GetUserByID), arg0, arg1)
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type LockoutRepo interface {
+	Reserve(ctx context.Context, key string, window time.Duration) (int, time.Duration, error)
+	Release(ctx context.Context, key string) error
+	Block(ctx context.Context, key string, d time.Duration) error
+	Reset(ctx context.Context, key string) error
+	GetBlocked(ctx context.Context) ([]lockout.Attempt, error)
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
+
+type UserRepo interface {
+	GetUserByID(ctx context.Context, id string) (*user.User, error)
+}
@@ -0,0 +1,51 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	"github.com/benderr/gophermart/internal/domain/lockout/usecase"
+	"github.com/benderr/gophermart/internal/domain/lockout/usecase/mocks"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLockoutRepo := mocks.NewMockLockoutRepo(ctrl)
+	policy := lockout.Policy{FreeAttempts: 2, BaseDelay: time.Second, MaxDelay: time.Minute}
+	lockoutUsecase := usecase.New(mockLockoutRepo, mocks.NewMockUserRepo(ctrl), mocktransactor.New(), policy, policy, mocklogger.New())
+
+	t.Run("Free attempt", func(t *testing.T) {
+		mockLockoutRepo.EXPECT().Reserve(gomock.Any(), "login:login", policy.Window).Return(1, time.Duration(0), nil)
+		mockLockoutRepo.EXPECT().Reserve(gomock.Any(), "ip:127.0.0.1", policy.Window).Return(1, time.Duration(0), nil)
+
+		assert.NoError(t, lockoutUsecase.Reserve(context.Background(), "login", "127.0.0.1"))
+	})
+
+	t.Run("Delay set before password check", func(t *testing.T) {
+		mockLockoutRepo.EXPECT().Reserve(gomock.Any(), "login:login", policy.Window).Return(3, time.Duration(0), nil)
+		mockLockoutRepo.EXPECT().Block(gomock.Any(), "login:login", time.Second).Return(nil)
+		mockLockoutRepo.EXPECT().Reserve(gomock.Any(), "ip:127.0.0.1", policy.Window).Return(1, time.Duration(0), nil)
+
+		assert.NoError(t, lockoutUsecase.Reserve(context.Background(), "login", "127.0.0.1"))
+	})
+
+	t.Run("Rejected while blocked", func(t *testing.T) {
+		mockLockoutRepo.EXPECT().Reserve(gomock.Any(), "login:login", policy.Window).Return(4, 30*time.Second, nil)
+
+		err := lockoutUsecase.Reserve(context.Background(), "login", "127.0.0.1")
+
+		var locked *lockout.LockedError
+		if assert.True(t, errors.As(err, &locked)) {
+			assert.Equal(t, 30*time.Second, locked.RetryAfter)
+		}
+	})
+}
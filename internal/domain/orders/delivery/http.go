@@ -41,6 +41,16 @@ func NewOrderHandlers(group *echo.Group, ou OrderUsecase, session SessionManager
 	g.POST("/orders", h.CreateOrderHandler)
 }
 
+// Просмотр заказов любого пользователя для поддержки и администраторов
+func NewOrderAdminHandlers(group *echo.Group, ou OrderUsecase, logger logger.Logger) {
+	h := &ordersHandler{
+		OrderUsecase: ou,
+		logger:       logger,
+	}
+
+	group.GET("/users/:id/orders", h.GetUserOrdersHandler)
+}
+
 func (o *ordersHandler) GetOrdersHandler(c echo.Context) error {
 	userid, err := o.session.GetUserID(c)
 	if err != nil {
@@ -112,3 +122,18 @@ func (o *ordersHandler) CreateOrderHandler(c echo.Context) error {
 	//новый контракт принят
 	return c.JSON(http.StatusAccepted, httputils.Ok())
 }
+
+func (o *ordersHandler) GetUserOrdersHandler(c echo.Context) error {
+	list, err := o.GetOrdersByUser(c.Request().Context(), c.Param("id"))
+
+	if err != nil {
+		o.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if len(list) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
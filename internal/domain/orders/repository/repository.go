@@ -9,6 +9,7 @@ import (
 
 	"github.com/benderr/gophermart/internal/domain/orders"
 	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -23,8 +24,17 @@ func New(db *sql.DB, log logger.Logger) *orderRepository {
 }
 
 func (u *orderRepository) GetByNumber(ctx context.Context, number string) (*orders.Order, error) {
+	return u.getByNumber(ctx, "SELECT order_num, user_id, status, accrual, uploaded_at from orders WHERE order_num = $1", number)
+}
+
+// Читает заказ с блокировкой строки до конца транзакции,
+// чтобы параллельные смены статуса не начислили и не списали баллы дважды
+func (u *orderRepository) GetByNumberForUpdate(ctx context.Context, number string) (*orders.Order, error) {
+	return u.getByNumber(ctx, "SELECT order_num, user_id, status, accrual, uploaded_at from orders WHERE order_num = $1 FOR UPDATE", number)
+}
 
-	row := u.db.QueryRowContext(ctx, "SELECT order_num, user_id, status, accrual, uploaded_at from orders WHERE order_num = $1", number)
+func (u *orderRepository) getByNumber(ctx context.Context, query string, number string) (*orders.Order, error) {
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, query, number)
 	var ord orders.Order
 	err := row.Scan(&ord.Number, &ord.UserID, &ord.Status, &ord.Accrual, &ord.UploadedAt)
 	if err != nil {
@@ -43,7 +53,7 @@ func (u *orderRepository) GetOrdersByStatuses(ctx context.Context, statuses ...o
 
 	statusarr, params := createInParams[orders.Status](statuses)
 	u.log.Infoln("configured sql", statusarr, params)
-	rows, err := u.db.QueryContext(ctx, `SELECT order_num, status, accrual, user_id, uploaded_at from orders WHERE status in (`+params+`)  ORDER BY uploaded_at desc`, statusarr...)
+	rows, err := transactor.FromContext(ctx, u.db).QueryContext(ctx, `SELECT order_num, status, accrual, user_id, uploaded_at from orders WHERE status in (`+params+`)  ORDER BY uploaded_at desc`, statusarr...)
 
 	if err != nil {
 		return nil, err
@@ -71,7 +81,7 @@ func (u *orderRepository) GetOrdersByStatuses(ctx context.Context, statuses ...o
 func (u *orderRepository) GetOrdersByUser(ctx context.Context, userid string) ([]orders.Order, error) {
 	orderlist := make([]orders.Order, 0)
 
-	rows, err := u.db.QueryContext(ctx, "SELECT order_num, status, accrual, user_id, uploaded_at from orders WHERE user_id=$1 ORDER BY uploaded_at desc", userid)
+	rows, err := transactor.FromContext(ctx, u.db).QueryContext(ctx, "SELECT order_num, status, accrual, user_id, uploaded_at from orders WHERE user_id=$1 ORDER BY uploaded_at desc", userid)
 
 	if err != nil {
 		return nil, err
@@ -97,7 +107,7 @@ func (u *orderRepository) GetOrdersByUser(ctx context.Context, userid string) ([
 }
 
 func (u *orderRepository) Create(ctx context.Context, userid string, number string, status orders.Status) (*orders.Order, error) {
-	_, err := u.db.ExecContext(ctx, `INSERT INTO orders (user_id, order_num, status) VALUES ($1, $2, $3)`, userid, number, status)
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `INSERT INTO orders (user_id, order_num, status) VALUES ($1, $2, $3)`, userid, number, status)
 	if err != nil {
 		var perr *pgconn.PgError
 		if errors.As(err, &perr) && perr.Code == pgerrcode.UniqueViolation {
@@ -114,13 +124,13 @@ func (u *orderRepository) Create(ctx context.Context, userid string, number stri
 	return created, nil
 }
 
-func (u *orderRepository) UpdateStatus(ctx context.Context, tx *sql.Tx, number string, status orders.Status) error {
-	_, err := tx.ExecContext(ctx, `UPDATE orders SET status=$1 WHERE order_num=$2`, status, number)
+func (u *orderRepository) UpdateStatus(ctx context.Context, number string, status orders.Status) error {
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE orders SET status=$1 WHERE order_num=$2`, status, number)
 	return err
 }
 
-func (u *orderRepository) UpdateAccrual(ctx context.Context, tx *sql.Tx, number string, accrual *float64) error {
-	_, err := tx.ExecContext(ctx, `UPDATE orders SET accrual=$1 WHERE order_num=$2`, accrual, number)
+func (u *orderRepository) UpdateAccrual(ctx context.Context, number string, accrual *float64) error {
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE orders SET accrual=$1 WHERE order_num=$2`, accrual, number)
 	return err
 }
 
@@ -2,33 +2,45 @@ package usecase
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 
 	"github.com/benderr/gophermart/internal/domain/orders"
+	"github.com/benderr/gophermart/internal/domain/points"
 	"github.com/benderr/gophermart/internal/logger"
 )
 
 type orderUsecase struct {
 	orderRepo   OrderRepo
 	balanceRepo BalanceRepo
+	points      Points
+	tiers       Tiers
+	campaigns   Campaigns
+	referrals   Referrals
+	clawbacks   Clawbacks
 	transactor  Transactor
 	publisher   Publisher
 	logger      logger.Logger
 }
 
-func New(op OrderRepo, br BalanceRepo, t Transactor, p Publisher, l logger.Logger) *orderUsecase {
+func New(op OrderRepo, br BalanceRepo, pts Points, tiers Tiers, cmp Campaigns, ref Referrals, cb Clawbacks, t Transactor, p Publisher, l logger.Logger) *orderUsecase {
 	return &orderUsecase{
 		orderRepo:   op,
 		balanceRepo: br,
+		points:      pts,
+		tiers:       tiers,
+		campaigns:   cmp,
+		referrals:   ref,
+		clawbacks:   cb,
 		transactor:  t,
 		publisher:   p,
 		logger:      l}
 }
 
+// Меняет статус заказа и начисляет или списывает баллы при переходе в PROCESSED/INVALID.
+// Строка заказа блокируется, поэтому переход применяется ровно один раз
 func (o *orderUsecase) ChangeStatus(ctx context.Context, number string, status orders.Status, accrual *float64) error {
-	return o.transactor.Within(ctx, func(ctx context.Context, tx *sql.Tx) error {
-		order, err := o.orderRepo.GetByNumber(ctx, number)
+	return o.transactor.Within(ctx, func(ctx context.Context) error {
+		order, err := o.orderRepo.GetByNumberForUpdate(ctx, number)
 		if err != nil {
 			return err
 		}
@@ -36,26 +48,32 @@ func (o *orderUsecase) ChangeStatus(ctx context.Context, number string, status o
 			return nil
 		}
 
-		err = o.orderRepo.UpdateStatus(ctx, tx, order.Number, status)
+		err = o.orderRepo.UpdateStatus(ctx, order.Number, status)
 
 		if err != nil {
 			return err
 		}
 
+		if status == orders.INVALID && order.Status == string(orders.PROCESSED) {
+			return o.reverse(ctx, order)
+		}
+
 		if accrual != nil && *accrual > 0 {
-			err = o.orderRepo.UpdateAccrual(ctx, tx, order.Number, accrual)
+			err = o.orderRepo.UpdateAccrual(ctx, order.Number, accrual)
 			if err != nil {
 				return err
 			}
 
 			if status == orders.PROCESSED && order.Status != string(orders.PROCESSED) {
-				err = o.balanceRepo.Add(ctx, tx, order.UserID, accrual)
-				if err != nil {
-					return err
-				}
+				return o.credit(ctx, order, *accrual)
 			}
 		}
 
+		if status == orders.PROCESSED && order.Status != string(orders.PROCESSED) {
+			//заказ без начисления тоже первый заказ приглашенного, приглашение отклоняется
+			return o.referrals.Reward(ctx, order.UserID, order.Number, 0)
+		}
+
 		return nil
 	})
 }
@@ -91,3 +109,49 @@ func (o *orderUsecase) Create(ctx context.Context, userid string, number string,
 func (o *orderUsecase) GetOrdersByUser(ctx context.Context, userid string) ([]orders.Order, error) {
 	return o.orderRepo.GetOrdersByUser(ctx, userid)
 }
+
+// Начисляет баллы за обработанный заказ с учетом множителя текущего уровня пользователя,
+// бонусы промо-кампаний и реферальной программы и пересчитывает уровень с учетом нового начисления
+func (o *orderUsecase) credit(ctx context.Context, order *orders.Order, accrual float64) error {
+	multiplier, err := o.tiers.Multiplier(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+
+	credited := accrual * multiplier
+
+	net, err := o.balanceRepo.Add(ctx, order.UserID, &credited)
+	if err != nil {
+		return err
+	}
+
+	err = o.points.Credit(ctx, order.UserID, points.SourceOrder, order.Number, credited, net)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.campaigns.ApplyBonuses(ctx, order.UserID, order.Number, accrual)
+	if err != nil {
+		return err
+	}
+
+	err = o.referrals.Reward(ctx, order.UserID, order.Number, accrual)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.tiers.Recalculate(ctx, order.UserID)
+	return err
+}
+
+// Отзывает начисление за заказ, признанный недействительным после обработки,
+// и пересчитывает уровень без учета этого заказа
+func (o *orderUsecase) reverse(ctx context.Context, order *orders.Order) error {
+	_, err := o.clawbacks.Reverse(ctx, order.UserID, order.Number)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.tiers.Recalculate(ctx, order.UserID)
+	return err
+}
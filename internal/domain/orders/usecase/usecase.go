@@ -2,25 +2,51 @@ package usecase
 
 import (
 	"context"
-	"database/sql"
 
+	"github.com/benderr/gophermart/internal/domain/clawback"
 	"github.com/benderr/gophermart/internal/domain/orders"
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/tier"
+	"github.com/benderr/gophermart/internal/transactor"
 )
 
 type OrderRepo interface {
-	UpdateStatus(ctx context.Context, tx *sql.Tx, number string, status orders.Status) error
-	UpdateAccrual(ctx context.Context, tx *sql.Tx, number string, accrual *float64) error
+	UpdateStatus(ctx context.Context, number string, status orders.Status) error
+	UpdateAccrual(ctx context.Context, number string, accrual *float64) error
 	Create(ctx context.Context, userid string, number string, status orders.Status) (*orders.Order, error)
 	GetByNumber(ctx context.Context, number string) (*orders.Order, error)
+	GetByNumberForUpdate(ctx context.Context, number string) (*orders.Order, error)
 	GetOrdersByUser(ctx context.Context, userid string) ([]orders.Order, error)
 }
 
 type BalanceRepo interface {
-	Add(ctx context.Context, tx *sql.Tx, userid string, balance *float64) error
+	//возвращает сумму, зачисленную на баланс после погашения долга
+	Add(ctx context.Context, userid string, balance *float64) (float64, error)
+}
+
+type Points interface {
+	Credit(ctx context.Context, userid string, source points.Source, order string, credited float64, net float64) error
+}
+
+type Tiers interface {
+	Multiplier(ctx context.Context, userid string) (float64, error)
+	Recalculate(ctx context.Context, userid string) (*tier.Status, error)
+}
+
+type Campaigns interface {
+	ApplyBonuses(ctx context.Context, userid string, number string, accrual float64) (float64, error)
+}
+
+type Referrals interface {
+	Reward(ctx context.Context, userid string, order string, accrual float64) error
+}
+
+type Clawbacks interface {
+	Reverse(ctx context.Context, userid string, order string) (*clawback.Clawback, error)
 }
 
 type Transactor interface {
-	Within(ctx context.Context, tFunc func(ctx context.Context, tx *sql.Tx) error) error
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
 }
 
 type Publisher interface {
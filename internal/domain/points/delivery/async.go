@@ -0,0 +1,54 @@
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type PointsUsecase interface {
+	ExpireDue(ctx context.Context) (int, error)
+}
+
+type expireTask struct {
+	points   PointsUsecase
+	interval time.Duration
+	logger   logger.Logger
+}
+
+func New(pu PointsUsecase, interval time.Duration, logger logger.Logger) *expireTask {
+	return &expireTask{
+		points:   pu,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Периодически списывает сгоревшие баллы, пока не будет отменен ctx
+func (e *expireTask) Run(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.expire(ctx)
+			}
+		}
+	}()
+}
+
+func (e *expireTask) expire(ctx context.Context) {
+	e.logger.Infoln("[START EXPIRE POINTS]")
+
+	count, err := e.points.ExpireDue(ctx)
+	if err != nil {
+		e.logger.Errorln("expire points error", err)
+	}
+
+	e.logger.Infoln("[FINISH EXPIRE POINTS]", count)
+}
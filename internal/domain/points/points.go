@@ -0,0 +1,129 @@
+package points
+
+import (
+	"sort"
+	"time"
+)
+
+// Источник начисления баллов
+type Source string
+
+const (
+	SourceOrder    Source = "order"
+	SourceTransfer Source = "transfer"
+	SourceCampaign Source = "campaign"
+	SourceReferral Source = "referral"
+	SourceWelcome  Source = "welcome"
+	SourceVoucher  Source = "voucher"
+)
+
+// Партия начисленных баллов. Списания расходуют партии в порядке SpendingOrder,
+// остаток партии сгорает по истечении ExpiresAt
+type Lot struct {
+	ID        string
+	UserID    string
+	Source    Source
+	Order     string
+	Amount    float64
+	Remaining float64
+	//начислено до погашения долга, Amount - после. Отзыв начисления считается от этой суммы
+	Credited  float64
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+// Баллы, которые сгорят в ближайшее время
+type Expiring struct {
+	Amount    float64
+	ExpiresAt *time.Time
+}
+
+// Порядок расходования партий: сначала сгорающие раньше, бессрочные последними,
+// при одинаковом сроке - более старые. Полученные переводом партии сохраняют срок исходных
+// и должны расходоваться раньше более поздних начислений. Возвращает отсортированную копию
+func SpendingOrder(lots []Lot) []Lot {
+	sorted := make([]Lot, len(lots))
+	copy(sorted, lots)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].ExpiresAt, sorted[j].ExpiresAt
+		switch {
+		case a == nil && b == nil:
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		case !a.Equal(*b):
+			return a.Before(*b)
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+	return sorted
+}
+
+// Списывает amount из партий в порядке их следования.
+// Возвращает только измененные партии с новым остатком
+func Consume(lots []Lot, amount float64) []Lot {
+	changed := make([]Lot, 0)
+	for _, l := range lots {
+		if amount <= 0 {
+			break
+		}
+		if l.Remaining <= 0 {
+			continue
+		}
+
+		take := l.Remaining
+		if take > amount {
+			take = amount
+		}
+
+		l.Remaining -= take
+		amount -= take
+		changed = append(changed, l)
+	}
+	return changed
+}
+
+// Партии получателя при переводе amount из партий отправителя: партии расходуются как в Consume,
+// каждая часть сохраняет срок сгорания исходной партии. Получателю зачислено net (остальное погасило долг),
+// долг гасится самыми старыми частями. Сумма сверх партий отправителя переходит без срока (ExpiresAt=nil)
+func Transferred(lots []Lot, amount float64, net float64) []Lot {
+	parts := make([]Lot, 0)
+	debt := amount - net
+	take := func(sum float64, expiresAt *time.Time) {
+		if debt > 0 {
+			repaid := sum
+			if repaid > debt {
+				repaid = debt
+			}
+			sum -= repaid
+			debt -= repaid
+		}
+		if sum > 0 {
+			parts = append(parts, Lot{Source: SourceTransfer, Amount: sum, Remaining: sum, ExpiresAt: expiresAt})
+		}
+	}
+
+	for _, l := range lots {
+		if amount <= 0 {
+			break
+		}
+		if l.Remaining <= 0 {
+			continue
+		}
+
+		sum := l.Remaining
+		if sum > amount {
+			sum = amount
+		}
+
+		amount -= sum
+		take(sum, l.ExpiresAt)
+	}
+
+	if amount > 0 {
+		take(amount, nil)
+	}
+
+	return parts
+}
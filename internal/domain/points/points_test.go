@@ -0,0 +1,112 @@
+package points_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsume(t *testing.T) {
+	lots := []points.Lot{
+		{ID: "1", Remaining: 30},
+		{ID: "2", Remaining: 0},
+		{ID: "3", Remaining: 50},
+		{ID: "4", Remaining: 100},
+	}
+
+	t.Run("Consume oldest first", func(t *testing.T) {
+		changed := points.Consume(lots, 60)
+
+		if assert.Len(t, changed, 2) {
+			assert.Equal(t, "1", changed[0].ID)
+			assert.Equal(t, float64(0), changed[0].Remaining)
+			assert.Equal(t, "3", changed[1].ID)
+			assert.Equal(t, float64(20), changed[1].Remaining)
+		}
+	})
+
+	t.Run("Consume more than tracked", func(t *testing.T) {
+		changed := points.Consume(lots, 1000)
+
+		assert.Len(t, changed, 3)
+		for _, l := range changed {
+			assert.Equal(t, float64(0), l.Remaining)
+		}
+	})
+
+	t.Run("Source lots untouched", func(t *testing.T) {
+		assert.Equal(t, float64(30), lots[0].Remaining)
+	})
+}
+
+func TestTransferred(t *testing.T) {
+	soon := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := soon.AddDate(0, 1, 0)
+	lots := []points.Lot{
+		{ID: "1", Remaining: 30, ExpiresAt: &soon},
+		{ID: "2", Remaining: 0},
+		{ID: "3", Remaining: 50, ExpiresAt: &later},
+	}
+
+	t.Run("Expiry carried over", func(t *testing.T) {
+		parts := points.Transferred(lots, 60, 60)
+
+		if assert.Len(t, parts, 2) {
+			assert.Equal(t, float64(30), parts[0].Amount)
+			assert.Equal(t, &soon, parts[0].ExpiresAt)
+			assert.Equal(t, float64(30), parts[1].Amount)
+			assert.Equal(t, &later, parts[1].ExpiresAt)
+			assert.Equal(t, points.SourceTransfer, parts[1].Source)
+		}
+	})
+
+	t.Run("Debt repaid from oldest parts", func(t *testing.T) {
+		parts := points.Transferred(lots, 60, 20)
+
+		if assert.Len(t, parts, 1) {
+			assert.Equal(t, float64(20), parts[0].Amount)
+			assert.Equal(t, &later, parts[0].ExpiresAt)
+		}
+	})
+
+	t.Run("More than tracked", func(t *testing.T) {
+		parts := points.Transferred(lots, 100, 100)
+
+		if assert.Len(t, parts, 3) {
+			assert.Equal(t, float64(20), parts[2].Amount)
+			assert.Nil(t, parts[2].ExpiresAt)
+		}
+	})
+}
+
+func TestSpendingOrder(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	soon := created.AddDate(0, 2, 0)
+	later := created.AddDate(0, 6, 0)
+	lots := []points.Lot{
+		{ID: "own", Remaining: 50, CreatedAt: created, ExpiresAt: &later},
+		{ID: "unlimited", Remaining: 50, CreatedAt: created},
+		//получена переводом позже, но сгорает раньше собственной партии
+		{ID: "received", Source: points.SourceTransfer, Remaining: 30, CreatedAt: created.AddDate(0, 1, 0), ExpiresAt: &soon},
+	}
+
+	t.Run("Received lot spent first", func(t *testing.T) {
+		changed := points.Consume(points.SpendingOrder(lots), 40)
+
+		if assert.Len(t, changed, 2) {
+			assert.Equal(t, "received", changed[0].ID)
+			assert.Equal(t, float64(0), changed[0].Remaining)
+			assert.Equal(t, "own", changed[1].ID)
+			assert.Equal(t, float64(40), changed[1].Remaining)
+		}
+	})
+
+	t.Run("Unlimited lots last", func(t *testing.T) {
+		sorted := points.SpendingOrder(lots)
+
+		assert.Equal(t, "unlimited", sorted[2].ID)
+		assert.Equal(t, "own", lots[0].ID)
+	})
+}
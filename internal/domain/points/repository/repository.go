@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type lotRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *lotRepository {
+	return &lotRepository{db: db, log: log}
+}
+
+// Создает партию со сроком lot.ExpiresAt, если он задан, иначе со сроком ttl (0 - без срока)
+func (l *lotRepository) Create(ctx context.Context, lot *points.Lot, ttl time.Duration) error {
+	var ttlSeconds *float64
+	if ttl > 0 {
+		s := ttl.Seconds()
+		ttlSeconds = &s
+	}
+
+	_, err := transactor.FromContext(ctx, l.db).ExecContext(ctx, `INSERT INTO balance_lots (user_id, source, order_num, amount, remaining, expires_at, credited)
+	VALUES($1, $2, NULLIF($3, ''), $4, $4, COALESCE($6, NOW() + $5 * interval '1 second'), NULLIF($7, 0))`, lot.UserID, lot.Source, lot.Order, lot.Amount, ttlSeconds, lot.ExpiresAt, lot.Credited)
+	l.log.Infoln("[CREATE LOT]", lot.UserID, lot.Amount)
+	return err
+}
+
+// Непогашенные и несгоревшие партии пользователя в порядке расходования (points.SpendingOrder), с блокировкой строк
+func (l *lotRepository) GetActiveByUser(ctx context.Context, userid string) ([]points.Lot, error) {
+	return l.queryLots(ctx, `SELECT id, user_id, source, COALESCE(order_num, ''), amount, remaining, created_at, expires_at
+	FROM balance_lots
+	WHERE user_id=$1 AND remaining > 0 AND (expires_at IS NULL OR expires_at > NOW())
+	ORDER BY expires_at ASC NULLS LAST, created_at ASC
+	FOR UPDATE`, userid)
+}
+
+// Сгоревшие партии с ненулевым остатком, строки, заблокированные другим обработчиком, пропускаем.
+// Партии пользователей с зарезервированными баллами не берем, пока резерв не списан или не снят
+func (l *lotRepository) GetExpired(ctx context.Context, limit int) ([]points.Lot, error) {
+	return l.queryLots(ctx, `SELECT id, user_id, source, COALESCE(order_num, ''), amount, remaining, created_at, expires_at
+	FROM balance_lots
+	WHERE remaining > 0 AND expires_at <= NOW()
+		AND NOT EXISTS (SELECT 1 FROM balance b WHERE b.user_id=balance_lots.user_id AND b.held > 0)
+	ORDER BY expires_at ASC
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED`, limit)
+}
+
+func (l *lotRepository) UpdateRemaining(ctx context.Context, id string, remaining float64) error {
+	_, err := transactor.FromContext(ctx, l.db).ExecContext(ctx, `UPDATE balance_lots SET remaining=$1 WHERE id=$2`, remaining, id)
+	return err
+}
+
+// Оставляет в партии lot.Remaining (зарезервированные баллы) и сохраняет запись о сгорании amount
+func (l *lotRepository) Expire(ctx context.Context, lot *points.Lot, amount float64) error {
+	_, err := transactor.FromContext(ctx, l.db).ExecContext(ctx, `UPDATE balance_lots SET remaining=$2 WHERE id=$1`, lot.ID, lot.Remaining)
+	if err != nil {
+		return err
+	}
+
+	if amount <= 0 {
+		return nil
+	}
+
+	_, err = transactor.FromContext(ctx, l.db).ExecContext(ctx, `INSERT INTO balance_expirations (user_id, lot_id, amount) VALUES($1, $2, $3)`, lot.UserID, lot.ID, amount)
+	l.log.Infoln("[EXPIRE LOT]", lot.ID, amount)
+	return err
+}
+
+func (l *lotRepository) GetExpiring(ctx context.Context, userid string, window time.Duration) (*points.Expiring, error) {
+	row := transactor.FromContext(ctx, l.db).QueryRowContext(ctx, `SELECT COALESCE(SUM(remaining), 0), MIN(expires_at)
+	FROM balance_lots
+	WHERE user_id=$1 AND remaining > 0 AND expires_at > NOW() AND expires_at <= NOW() + $2 * interval '1 second'`, userid, window.Seconds())
+
+	var exp points.Expiring
+	var expiresAt sql.NullTime
+	err := row.Scan(&exp.Amount, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		exp.ExpiresAt = &expiresAt.Time
+	}
+
+	return &exp, nil
+}
+
+func (l *lotRepository) queryLots(ctx context.Context, query string, args ...any) ([]points.Lot, error) {
+	list := make([]points.Lot, 0)
+
+	rows, err := transactor.FromContext(ctx, l.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var lot points.Lot
+		var expiresAt sql.NullTime
+		err = rows.Scan(&lot.ID, &lot.UserID, &lot.Source, &lot.Order, &lot.Amount, &lot.Remaining, &lot.CreatedAt, &expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt.Valid {
+			lot.ExpiresAt = &expiresAt.Time
+		}
+
+		list = append(list, lot)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+const expireBatchSize = 100
+
+type pointsUsecase struct {
+	lotRepo     LotRepo
+	balanceRepo BalanceRepo
+	transactor  Transactor
+	ttl         time.Duration
+	warnWindow  time.Duration
+	logger      logger.Logger
+}
+
+func New(lr LotRepo, br BalanceRepo, t Transactor, ttl time.Duration, warnWindow time.Duration, l logger.Logger) *pointsUsecase {
+	return &pointsUsecase{
+		lotRepo:     lr,
+		balanceRepo: br,
+		transactor:  t,
+		ttl:         ttl,
+		warnWindow:  warnWindow,
+		logger:      l}
+}
+
+// Регистрирует начисление как новую партию баллов, сам баланс меняет вызывающая сторона.
+// credited - начисленная сумма, net - остаток после погашения долга. Партия создается и тогда,
+// когда все начисление ушло на долг, чтобы отзыв начисления знал его полную сумму
+func (p *pointsUsecase) Credit(ctx context.Context, userid string, source points.Source, order string, credited float64, net float64) error {
+	if credited <= 0 {
+		return nil
+	}
+
+	return p.lotRepo.Create(ctx, &points.Lot{
+		UserID:   userid,
+		Source:   source,
+		Order:    order,
+		Amount:   net,
+		Credited: credited,
+	}, p.ttl)
+}
+
+// Списывает amount из партий пользователя, начиная с ближайших к сгоранию
+func (p *pointsUsecase) Consume(ctx context.Context, userid string, amount float64) error {
+	lots, err := p.lotRepo.GetActiveByUser(ctx, userid)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range points.Consume(points.SpendingOrder(lots), amount) {
+		err = p.lotRepo.UpdateRemaining(ctx, l.ID, l.Remaining)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Списывает amount при отмене начисления за заказ: сначала из партий этого заказа, затем из ближайших к сгоранию
+func (p *pointsUsecase) ConsumeOrder(ctx context.Context, userid string, order string, amount float64) error {
+	lots, err := p.lotRepo.GetActiveByUser(ctx, userid)
+	if err != nil {
+		return err
+	}
+
+	lots = points.SpendingOrder(lots)
+
+	ordered := make([]points.Lot, 0, len(lots))
+	for _, l := range lots {
+		if l.Order == order {
+			ordered = append(ordered, l)
+		}
+	}
+	for _, l := range lots {
+		if l.Order != order {
+			ordered = append(ordered, l)
+		}
+	}
+
+	for _, l := range points.Consume(ordered, amount) {
+		err = p.lotRepo.UpdateRemaining(ctx, l.ID, l.Remaining)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Переводит партии между пользователями: у отправителя amount списывается из ближайших к сгоранию партий,
+// получателю создаются партии на net с теми же сроками сгорания, чтобы перевод не продлевал срок баллов
+func (p *pointsUsecase) Transfer(ctx context.Context, from string, to string, amount float64, net float64) error {
+	lots, err := p.lotRepo.GetActiveByUser(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	lots = points.SpendingOrder(lots)
+
+	for _, l := range points.Consume(lots, amount) {
+		err = p.lotRepo.UpdateRemaining(ctx, l.ID, l.Remaining)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, part := range points.Transferred(lots, amount, net) {
+		part := part
+		part.UserID = to
+		err = p.lotRepo.Create(ctx, &part, p.ttl)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *pointsUsecase) GetExpiring(ctx context.Context, userid string) (*points.Expiring, error) {
+	return p.lotRepo.GetExpiring(ctx, userid, p.warnWindow)
+}
+
+// Списывает с баланса остатки сгоревших партий пачками, каждая пачка в своей транзакции.
+// Партии пользователей с резервом ждут его списания или снятия.
+// Возвращает количество обработанных партий
+func (p *pointsUsecase) ExpireDue(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		count, err := p.expireBatch(ctx)
+		total += count
+		if err != nil || count < expireBatchSize {
+			return total, err
+		}
+	}
+}
+
+func (p *pointsUsecase) expireBatch(ctx context.Context) (int, error) {
+	count := 0
+	err := p.transactor.Within(ctx, func(ctx context.Context) error {
+		lots, err := p.lotRepo.GetExpired(ctx, expireBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, l := range lots {
+			l := l
+			expired, held, err := p.balanceRepo.Expire(ctx, l.UserID, l.Remaining)
+			if err != nil {
+				return err
+			}
+
+			//зарезервированные баллы остаются в партии до списания или снятия резерва
+			l.Remaining = held
+			err = p.lotRepo.Expire(ctx, &l, expired)
+			if err != nil {
+				return err
+			}
+		}
+
+		count = len(lots)
+		return nil
+	})
+
+	return count, err
+}
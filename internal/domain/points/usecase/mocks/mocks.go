@@ -0,0 +1,167 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/points/usecase (interfaces: LotRepo,BalanceRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/points/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/points/usecase LotRepo,BalanceRepo
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	points "github.com/benderr/gophermart/internal/domain/points"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLotRepo is a mock of LotRepo interface.
+type MockLotRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLotRepoMockRecorder
+}
+
+// MockLotRepoMockRecorder is the mock recorder for MockLotRepo.
+type MockLotRepoMockRecorder struct {
+	mock *MockLotRepo
+}
+
+// NewMockLotRepo creates a new mock instance.
+func NewMockLotRepo(ctrl *gomock.Controller) *MockLotRepo {
+	mock := &MockLotRepo{ctrl: ctrl}
+	mock.recorder = &MockLotRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLotRepo) EXPECT() *MockLotRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockLotRepo) Create(arg0 context.Context, arg1 *points.Lot, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLotRepoMockRecorder) Create(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLotRepo)(nil).Create), arg0, arg1, arg2)
+}
+
+// Expire mocks base method.
+func (m *MockLotRepo) Expire(arg0 context.Context, arg1 *points.Lot, arg2 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockLotRepoMockRecorder) Expire(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockLotRepo)(nil).Expire), arg0, arg1, arg2)
+}
+
+// GetActiveByUser mocks base method.
+func (m *MockLotRepo) GetActiveByUser(arg0 context.Context, arg1 string) ([]points.Lot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveByUser", arg0, arg1)
+	ret0, _ := ret[0].([]points.Lot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveByUser indicates an expected call of GetActiveByUser.
+func (mr *MockLotRepoMockRecorder) GetActiveByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByUser", reflect.TypeOf((*MockLotRepo)(nil).GetActiveByUser), arg0, arg1)
+}
+
+// GetExpired mocks base method.
+func (m *MockLotRepo) GetExpired(arg0 context.Context, arg1 int) ([]points.Lot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", arg0, arg1)
+	ret0, _ := ret[0].([]points.Lot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockLotRepoMockRecorder) GetExpired(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockLotRepo)(nil).GetExpired), arg0, arg1)
+}
+
+// GetExpiring mocks base method.
+func (m *MockLotRepo) GetExpiring(arg0 context.Context, arg1 string, arg2 time.Duration) (*points.Expiring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiring", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*points.Expiring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiring indicates an expected call of GetExpiring.
+func (mr *MockLotRepoMockRecorder) GetExpiring(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiring", reflect.TypeOf((*MockLotRepo)(nil).GetExpiring), arg0, arg1, arg2)
+}
+
+// UpdateRemaining mocks base method.
+func (m *MockLotRepo) UpdateRemaining(arg0 context.Context, arg1 string, arg2 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRemaining", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRemaining indicates an expected call of UpdateRemaining.
+func (mr *MockLotRepoMockRecorder) UpdateRemaining(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRemaining", reflect.TypeOf((*MockLotRepo)(nil).UpdateRemaining), arg0, arg1, arg2)
+}
+
+// MockBalanceRepo is a mock of BalanceRepo interface.
+type MockBalanceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceRepoMockRecorder
+}
+
+// MockBalanceRepoMockRecorder is the mock recorder for MockBalanceRepo.
+type MockBalanceRepoMockRecorder struct {
+	mock *MockBalanceRepo
+}
+
+// NewMockBalanceRepo creates a new mock instance.
+func NewMockBalanceRepo(ctrl *gomock.Controller) *MockBalanceRepo {
+	mock := &MockBalanceRepo{ctrl: ctrl}
+	mock.recorder = &MockBalanceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceRepo) EXPECT() *MockBalanceRepoMockRecorder {
+	return m.recorder
+}
+
+// Expire mocks base method.
+func (m *MockBalanceRepo) Expire(arg0 context.Context, arg1 string, arg2 float64) (float64, float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockBalanceRepoMockRecorder) Expire(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockBalanceRepo)(nil).Expire), arg0, arg1, arg2)
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type LotRepo interface {
+	Create(ctx context.Context, lot *points.Lot, ttl time.Duration) error
+	GetActiveByUser(ctx context.Context, userid string) ([]points.Lot, error)
+	GetExpired(ctx context.Context, limit int) ([]points.Lot, error)
+	UpdateRemaining(ctx context.Context, id string, remaining float64) error
+	Expire(ctx context.Context, lot *points.Lot, amount float64) error
+	GetExpiring(ctx context.Context, userid string, window time.Duration) (*points.Expiring, error)
+}
+
+type BalanceRepo interface {
+	Expire(ctx context.Context, userid string, amount float64) (float64, float64, error)
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
@@ -0,0 +1,69 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/points/usecase"
+	"github.com/benderr/gophermart/internal/domain/points/usecase/mocks"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCredit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLotRepo := mocks.NewMockLotRepo(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	pointsUsecase := usecase.New(mockLotRepo, mockBalanceRepo, mocktransactor.New(), time.Hour, time.Hour, mocklogger.New())
+
+	t.Run("Accrual repays debt", func(t *testing.T) {
+		//все начисление ушло на долг, партия пустая, но помнит начисленную сумму для отзыва
+		mockLotRepo.EXPECT().Create(gomock.Any(), &points.Lot{
+			UserID:   "user",
+			Source:   points.SourceOrder,
+			Order:    "123",
+			Amount:   0,
+			Credited: 100,
+		}, time.Hour).Return(nil)
+
+		err := pointsUsecase.Credit(context.Background(), "user", points.SourceOrder, "123", 100, 0)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Nothing credited", func(t *testing.T) {
+		err := pointsUsecase.Credit(context.Background(), "user", points.SourceOrder, "123", 0, 0)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestExpireDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLotRepo := mocks.NewMockLotRepo(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	pointsUsecase := usecase.New(mockLotRepo, mockBalanceRepo, mocktransactor.New(), time.Hour, time.Hour, mocklogger.New())
+
+	t.Run("Held points stay in lot", func(t *testing.T) {
+		lot := points.Lot{ID: "lot", UserID: "user", Amount: 50, Remaining: 50}
+
+		//резерв не дал списать 30 из 50, они остаются в партии до списания или снятия резерва
+		mockLotRepo.EXPECT().GetExpired(gomock.Any(), gomock.Any()).Return([]points.Lot{lot}, nil)
+		mockBalanceRepo.EXPECT().Expire(gomock.Any(), "user", 50.0).Return(20.0, 30.0, nil)
+		mockLotRepo.EXPECT().Expire(gomock.Any(), &points.Lot{ID: "lot", UserID: "user", Amount: 50, Remaining: 30}, 20.0).Return(nil)
+
+		count, err := pointsUsecase.ExpireDue(context.Background())
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, count)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/reconciliation"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type ReconciliationUsecase interface {
+	Reconcile(ctx context.Context, repair bool) (*reconciliation.Report, error)
+}
+
+type reconcileTask struct {
+	reconciliation ReconciliationUsecase
+	interval       time.Duration
+	repair         bool
+	logger         logger.Logger
+}
+
+func NewReconcileTask(ru ReconciliationUsecase, interval time.Duration, repair bool, logger logger.Logger) *reconcileTask {
+	return &reconcileTask{
+		reconciliation: ru,
+		interval:       interval,
+		repair:         repair,
+		logger:         logger,
+	}
+}
+
+// Периодически сверяет балансы, пока не будет отменен ctx
+func (r *reconcileTask) Run(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.reconcile(ctx)
+			}
+		}
+	}()
+}
+
+func (r *reconcileTask) reconcile(ctx context.Context) {
+	r.logger.Infoln("[START RECONCILE]")
+
+	report, err := r.reconciliation.Reconcile(ctx, r.repair)
+	if err != nil {
+		r.logger.Errorln("reconcile error", err)
+		return
+	}
+
+	r.logger.Infow("[FINISH RECONCILE]", "users", report.Users, "mismatches", len(report.Mismatches))
+}
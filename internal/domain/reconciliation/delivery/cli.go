@@ -0,0 +1,27 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+)
+
+// Подкоманда reconcile: печатает отчет сверки в формате JSON.
+// Флаг -repair исправляет найденные расхождения
+func RunCommand(ctx context.Context, ru ReconciliationUsecase, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "repair mismatches with adjustment entries")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := ru.Reconcile(ctx, *repair)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,70 @@
+package reconciliation
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// Допустимая погрешность сравнения сумм с плавающей точкой
+const tolerance = 1e-6
+
+// Фактический и ожидаемый по журналу операций баланс пользователя
+type Mismatch struct {
+	UserID            string  `json:"user_id"`
+	Current           float64 `json:"current"`
+	ExpectedCurrent   float64 `json:"expected_current"`
+	Withdrawn         float64 `json:"withdrawn"`
+	ExpectedWithdrawn float64 `json:"expected_withdrawn"`
+	Repaired          bool    `json:"repaired"`
+}
+
+// Поправка текущего баланса, приводящая его к ожидаемому
+func (m Mismatch) CurrentDiff() float64 {
+	return m.ExpectedCurrent - m.Current
+}
+
+// Поправка суммы списаний, приводящая ее к ожидаемой
+func (m Mismatch) WithdrawnDiff() float64 {
+	return m.ExpectedWithdrawn - m.Withdrawn
+}
+
+func (m Mismatch) IsMismatch() bool {
+	return math.Abs(m.CurrentDiff()) > tolerance || math.Abs(m.WithdrawnDiff()) > tolerance
+}
+
+// Суммы журнала операций пользователя, из которых складывается ожидаемый баланс
+type Ledger struct {
+	//партии баллов всех источников, включая заказы, признанные недействительными после начисления,
+	//и начисления за заказы, обработанные до появления партий. Партия хранит сумму после погашения долга,
+	//поэтому погашенный долг отдельно не вычитается
+	Credited float64
+	//ручные корректировки, кроме корректировок сверки
+	Adjusted          float64
+	AdjustedWithdrawn float64
+	Withdrawn         float64
+	TransferredOut    float64
+	Expired           float64
+	//часть отзыва начислений, списанная с баланса
+	ClawedBack float64
+}
+
+func (l Ledger) ExpectedCurrent() float64 {
+	return l.Credited + l.Adjusted - l.Withdrawn - l.TransferredOut - l.Expired - l.ClawedBack
+}
+
+func (l Ledger) ExpectedWithdrawn() float64 {
+	return l.Withdrawn + l.AdjustedWithdrawn
+}
+
+// Результат сверки балансов
+type Report struct {
+	CheckedAt  time.Time  `json:"checked_at"`
+	Users      int        `json:"users"`
+	Repair     bool       `json:"repair"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
@@ -0,0 +1,77 @@
+package reconciliation
+
+import (
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/clawback"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Mismatch
+		mismatch bool
+	}{
+		{name: "equal", m: Mismatch{Current: 100, ExpectedCurrent: 100, Withdrawn: 50, ExpectedWithdrawn: 50}, mismatch: false},
+		{name: "float rounding", m: Mismatch{Current: 0.1 + 0.2, ExpectedCurrent: 0.3}, mismatch: false},
+		{name: "current differs", m: Mismatch{Current: 90, ExpectedCurrent: 100}, mismatch: true},
+		{name: "withdrawn differs", m: Mismatch{Withdrawn: 10, ExpectedWithdrawn: 0}, mismatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.mismatch, tt.m.IsMismatch())
+		})
+	}
+
+	m := Mismatch{Current: 90, ExpectedCurrent: 100, Withdrawn: 30, ExpectedWithdrawn: 20}
+	assert.Equal(t, 10.0, m.CurrentDiff())
+	assert.Equal(t, -10.0, m.WithdrawnDiff())
+}
+
+func TestLedgerClawback(t *testing.T) {
+	rules := clawback.Rules{Policy: clawback.BLOCK}
+
+	t.Run("Accrual then invalid order", func(t *testing.T) {
+		//начисление 100 за заказ, затем заказ признан недействительным
+		current := 100.0
+		cb := rules.Apply(100, current)
+		current -= cb.Debited
+
+		l := Ledger{Credited: 100, ClawedBack: cb.Debited}
+		m := Mismatch{Current: current, ExpectedCurrent: l.ExpectedCurrent()}
+
+		assert.False(t, m.IsMismatch())
+		assert.Equal(t, 0.0, m.CurrentDiff())
+	})
+
+	t.Run("Accrual spent then invalid order", func(t *testing.T) {
+		//из начисления 100 списано 80, отзыв списывает остаток 20 и оставляет долг 80
+		current := 100.0 - 80
+		cb := rules.Apply(100, current)
+		current -= cb.Debited
+
+		l := Ledger{Credited: 100, Withdrawn: 80, ClawedBack: cb.Debited}
+		m := Mismatch{Current: current, ExpectedCurrent: l.ExpectedCurrent(), Withdrawn: 80, ExpectedWithdrawn: l.ExpectedWithdrawn()}
+
+		assert.Equal(t, 80.0, cb.Debt)
+		assert.False(t, m.IsMismatch())
+	})
+
+	t.Run("Accrual repays debt", func(t *testing.T) {
+		//из начисления 100 списано 70, отзыв списывает остаток 30 и оставляет долг 70
+		current := 100.0 - 70
+		cb := rules.Apply(100, current)
+		current -= cb.Debited
+
+		//новое начисление 100 сначала гасит долг, партия создается на остаток 30
+		net := 100 - cb.Debt
+		current += net
+
+		l := Ledger{Credited: 100 + net, Withdrawn: 70, ClawedBack: cb.Debited}
+		m := Mismatch{Current: current, ExpectedCurrent: l.ExpectedCurrent(), Withdrawn: 70, ExpectedWithdrawn: l.ExpectedWithdrawn()}
+
+		assert.Equal(t, 30.0, current)
+		assert.False(t, m.IsMismatch())
+	})
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/benderr/gophermart/internal/domain/adjustment"
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/reconciliation"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type reconciliationRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *reconciliationRepository {
+	return &reconciliationRepository{db: db, log: log}
+}
+
+// Суммы журнала операций для ожидаемого баланса (см. reconciliation.Ledger).
+// Партии заказов учитываются при любом статусе заказа: отзыв начисления учтен в clawbacks.
+// Для заказов без партии (начисленных до появления партий) берем accrual заказа.
+// Корректировки сверки не учитываются, иначе исправленное расхождение попадало бы в ожидаемый баланс.
+// userid = nil - все пользователи
+const ledgerQuery = `WITH
+	legacy AS (
+		SELECT o.user_id, SUM(o.accrual) AS sum
+		FROM orders o
+		WHERE o.accrual IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM balance_lots l WHERE l.order_num = o.order_num AND l.source = $1)
+		GROUP BY o.user_id),
+	lots AS (
+		SELECT user_id, SUM(amount) AS sum FROM balance_lots GROUP BY user_id),
+	wd AS (
+		SELECT user_id, SUM(sum) AS sum FROM withdrawals GROUP BY user_id),
+	tout AS (
+		SELECT from_user_id AS user_id, SUM(amount) AS sum FROM transfers GROUP BY from_user_id),
+	exp AS (
+		SELECT user_id, SUM(amount) AS sum FROM balance_expirations GROUP BY user_id),
+	adj AS (
+		SELECT user_id, SUM(amount) AS current, SUM(withdrawn) AS withdrawn
+		FROM balance_adjustments WHERE reason <> $2 GROUP BY user_id),
+	cb AS (
+		SELECT user_id, SUM(debited) AS debited FROM clawbacks GROUP BY user_id)
+	SELECT u.id, COALESCE(b.current, 0), COALESCE(b.withdrawn, 0),
+		COALESCE(lots.sum, 0) + COALESCE(legacy.sum, 0),
+		COALESCE(adj.current, 0), COALESCE(adj.withdrawn, 0),
+		COALESCE(wd.sum, 0), COALESCE(tout.sum, 0), COALESCE(exp.sum, 0),
+		COALESCE(cb.debited, 0)
+	FROM users u
+	LEFT JOIN balance b ON b.user_id = u.id
+	LEFT JOIN legacy ON legacy.user_id = u.id
+	LEFT JOIN lots ON lots.user_id = u.id
+	LEFT JOIN wd ON wd.user_id = u.id
+	LEFT JOIN tout ON tout.user_id = u.id
+	LEFT JOIN exp ON exp.user_id = u.id
+	LEFT JOIN adj ON adj.user_id = u.id
+	LEFT JOIN cb ON cb.user_id = u.id
+	WHERE $3::uuid IS NULL OR u.id = $3::uuid
+	ORDER BY u.id`
+
+// Фактический и ожидаемый баланс всех пользователей
+func (r *reconciliationRepository) GetAll(ctx context.Context) ([]reconciliation.Mismatch, error) {
+	return r.query(ctx, nil)
+}
+
+// Фактический и ожидаемый баланс одного пользователя
+func (r *reconciliationRepository) GetByUser(ctx context.Context, userid string) (*reconciliation.Mismatch, error) {
+	list, err := r.query(ctx, &userid)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, reconciliation.ErrUserNotFound
+	}
+	return &list[0], nil
+}
+
+func (r *reconciliationRepository) query(ctx context.Context, userid *string) ([]reconciliation.Mismatch, error) {
+	list := make([]reconciliation.Mismatch, 0)
+
+	rows, err := transactor.FromContext(ctx, r.db).QueryContext(ctx, ledgerQuery,
+		points.SourceOrder, adjustment.RECONCILIATION, userid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var m reconciliation.Mismatch
+		var l reconciliation.Ledger
+		err = rows.Scan(&m.UserID, &m.Current, &m.Withdrawn, &l.Credited, &l.Adjusted, &l.AdjustedWithdrawn,
+			&l.Withdrawn, &l.TransferredOut, &l.Expired, &l.ClawedBack)
+		if err != nil {
+			return nil, err
+		}
+
+		m.ExpectedCurrent = l.ExpectedCurrent()
+		m.ExpectedWithdrawn = l.ExpectedWithdrawn()
+
+		list = append(list, m)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/adjustment"
+	"github.com/benderr/gophermart/internal/domain/reconciliation"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+// Автор корректировок, созданных сверкой
+const actor = "system:reconciliation"
+
+type reconciliationUsecase struct {
+	reconciliationRepo ReconciliationRepo
+	balanceRepo        BalanceRepo
+	adjustmentRepo     AdjustmentRepo
+	transactor         Transactor
+	logger             logger.Logger
+}
+
+func New(rr ReconciliationRepo, br BalanceRepo, ar AdjustmentRepo, t Transactor, l logger.Logger) *reconciliationUsecase {
+	return &reconciliationUsecase{
+		reconciliationRepo: rr,
+		balanceRepo:        br,
+		adjustmentRepo:     ar,
+		transactor:         t,
+		logger:             l}
+}
+
+// Сверяет балансы всех пользователей с журналом операций.
+// При repair расхождения исправляются корректировками с причиной RECONCILIATION
+func (r *reconciliationUsecase) Reconcile(ctx context.Context, repair bool) (*reconciliation.Report, error) {
+	report := &reconciliation.Report{
+		CheckedAt:  time.Now(),
+		Repair:     repair,
+		Mismatches: make([]reconciliation.Mismatch, 0),
+	}
+
+	list, err := r.reconciliationRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Users = len(list)
+
+	for _, m := range list {
+		if !m.IsMismatch() {
+			continue
+		}
+
+		r.logger.Infow("[BALANCE MISMATCH]", "mismatch", m)
+
+		if repair {
+			repaired, err := r.repair(ctx, m.UserID)
+			if err != nil {
+				r.logger.Errorln("repair balance error", m.UserID, err)
+			}
+			m.Repaired = repaired
+		}
+
+		report.Mismatches = append(report.Mismatches, m)
+	}
+
+	return report, nil
+}
+
+// Пересчитывает расхождение под блокировкой баланса, так как с момента общей выборки
+// баланс мог измениться, и записывает корректировку на разницу
+func (r *reconciliationUsecase) repair(ctx context.Context, userid string) (bool, error) {
+	repaired := false
+	err := r.transactor.Within(ctx, func(ctx context.Context) error {
+		err := r.balanceRepo.Lock(ctx, userid)
+		if err != nil {
+			return err
+		}
+
+		m, err := r.reconciliationRepo.GetByUser(ctx, userid)
+		if err != nil {
+			return err
+		}
+
+		if !m.IsMismatch() {
+			return nil
+		}
+
+		_, err = r.adjustmentRepo.Create(ctx, &adjustment.Adjustment{
+			UserID:    userid,
+			Amount:    m.CurrentDiff(),
+			Withdrawn: m.WithdrawnDiff(),
+			Reason:    adjustment.RECONCILIATION,
+			Comment:   "balance reconciliation",
+			Actor:     actor,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = r.balanceRepo.Adjust(ctx, userid, m.CurrentDiff(), m.WithdrawnDiff())
+		if err != nil {
+			return err
+		}
+
+		repaired = true
+		return nil
+	})
+
+	return repaired, err
+}
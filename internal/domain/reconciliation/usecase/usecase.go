@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/adjustment"
+	"github.com/benderr/gophermart/internal/domain/reconciliation"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type ReconciliationRepo interface {
+	GetAll(ctx context.Context) ([]reconciliation.Mismatch, error)
+	GetByUser(ctx context.Context, userid string) (*reconciliation.Mismatch, error)
+}
+
+type BalanceRepo interface {
+	Lock(ctx context.Context, userid string) error
+	Adjust(ctx context.Context, userid string, amount float64, withdrawn float64) error
+}
+
+type AdjustmentRepo interface {
+	Create(ctx context.Context, adj *adjustment.Adjustment) (*adjustment.Adjustment, error)
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
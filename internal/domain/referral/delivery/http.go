@@ -0,0 +1,53 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/referral"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type ReferralUsecase interface {
+	GetStats(ctx context.Context, userid string) (*referral.Stats, error)
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type referralHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	ReferralUsecase
+}
+
+func NewReferralHandlers(group *echo.Group, ru ReferralUsecase, session SessionManager, logger logger.Logger) {
+	h := &referralHandler{
+		ReferralUsecase: ru,
+		session:         session,
+		logger:          logger,
+	}
+
+	g := group.Group("/api/user")
+
+	g.GET("/referrals", h.GetStatsHandler)
+}
+
+func (r *referralHandler) GetStatsHandler(c echo.Context) error {
+	userid, err := r.session.GetUserID(c)
+	if err != nil {
+		r.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	stats, err := r.GetStats(c.Request().Context(), userid)
+	if err != nil {
+		r.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,75 @@
+package referral
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+)
+
+type Status string
+
+const (
+	//приглашенный пользователь еще не получил первое начисление
+	PENDING Status = "PENDING"
+	//бонусы начислены
+	REWARDED Status = "REWARDED"
+	//первый заказ не прошел условия программы
+	REJECTED Status = "REJECTED"
+)
+
+// Приглашение пользователя по реферальному коду
+type Referral struct {
+	ID            string
+	ReferrerID    string
+	ReferredID    string
+	Status        Status
+	Order         string
+	ReferrerBonus float64
+	ReferredBonus float64
+	CreatedAt     time.Time
+	RewardedAt    *time.Time
+}
+
+// Статистика приглашений для пригласившего пользователя
+type Stats struct {
+	Code     string  `json:"code"`
+	Invited  int     `json:"invited"`
+	Pending  int     `json:"pending"`
+	Rewarded int     `json:"rewarded"`
+	Earned   float64 `json:"earned"`
+}
+
+// Условия программы. Нулевой бонус не начисляется, MaxPerUser=0 - без ограничения количества наград,
+// MaxPerDay=0 - без ограничения количества приглашений за сутки
+type Program struct {
+	ReferrerBonus float64
+	ReferredBonus float64
+	MaxPerUser    int
+	MaxPerDay     int
+	MinAccrual    float64
+}
+
+var (
+	ErrInvalidCode  = errors.New("invalid referral code")
+	ErrSelfReferral = errors.New("self referral not allowed")
+	ErrTooMany      = errors.New("too many referrals")
+	ErrCodeExist    = errors.New("referral code already exist")
+)
+
+const (
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLength   = 8
+)
+
+// Случайный код без похожих символов (0/O, 1/I)
+func GenerateCode() (string, error) {
+	buf := make([]byte, codeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	for i, b := range buf {
+		buf[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/orders"
+	"github.com/benderr/gophermart/internal/domain/referral"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type referralRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *referralRepository {
+	return &referralRepository{db: db, log: log}
+}
+
+// Код пользователя, пустая строка - код еще не выдан
+func (r *referralRepository) GetCode(ctx context.Context, userid string) (string, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT COALESCE(referral_code, '') FROM users WHERE id=$1`, userid)
+	var code string
+	err := row.Scan(&code)
+	return code, err
+}
+
+func (r *referralRepository) SetCode(ctx context.Context, userid string, code string) error {
+	_, err := transactor.FromContext(ctx, r.db).ExecContext(ctx, `UPDATE users SET referral_code=$1 WHERE id=$2 AND referral_code IS NULL`, code, userid)
+	if err != nil {
+		var perr *pgconn.PgError
+		if errors.As(err, &perr) && perr.Code == pgerrcode.UniqueViolation {
+			return referral.ErrCodeExist
+		}
+		return err
+	}
+	return nil
+}
+
+// Владелец кода, пустая строка - код не найден
+func (r *referralRepository) GetUserByCode(ctx context.Context, code string) (string, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT id FROM users WHERE referral_code=$1`, code)
+	var userid string
+	err := row.Scan(&userid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return userid, nil
+}
+
+// Блокирует строку пригласившего до конца транзакции и возвращает IP, с которого он регистрировался.
+// Пустая строка - IP неизвестен (пользователи, зарегистрированные до его сохранения)
+func (r *referralRepository) LockReferrer(ctx context.Context, referrerID string) (string, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT COALESCE(registered_ip, '') FROM users WHERE id=$1 FOR UPDATE`, referrerID)
+	var ip string
+	err := row.Scan(&ip)
+	return ip, err
+}
+
+// Количество приглашений пользователя, созданных после since
+func (r *referralRepository) CountSince(ctx context.Context, referrerID string, since time.Time) (int, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM referrals WHERE referrer_id=$1 AND created_at>=$2`, referrerID, since)
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+// Количество приглашенных пользователем, зарегистрировавшихся с указанного IP
+func (r *referralRepository) CountFromIP(ctx context.Context, referrerID string, ip string) (int, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM referrals rf
+	JOIN users u ON u.id = rf.referred_id
+	WHERE rf.referrer_id=$1 AND u.registered_ip=$2`, referrerID, ip)
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *referralRepository) Create(ctx context.Context, referrerID string, referredID string) error {
+	_, err := transactor.FromContext(ctx, r.db).ExecContext(ctx, `INSERT INTO referrals (referrer_id, referred_id, status) VALUES($1, $2, $3)`,
+		referrerID, referredID, referral.PENDING)
+	r.log.Infoln("[CREATE REFERRAL]", referrerID, referredID)
+	return err
+}
+
+// Ожидающее приглашение пользователя с блокировкой строки, nil - приглашения нет
+func (r *referralRepository) GetPendingByReferred(ctx context.Context, userid string) (*referral.Referral, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT id, referrer_id, referred_id, status, created_at
+	FROM referrals WHERE referred_id=$1 AND status=$2 FOR UPDATE`, userid, referral.PENDING)
+
+	var v referral.Referral
+	err := row.Scan(&v.ID, &v.ReferrerID, &v.ReferredID, &v.Status, &v.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Количество обработанных заказов пользователя
+func (r *referralRepository) CountProcessedOrders(ctx context.Context, userid string) (int, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM orders WHERE user_id=$1 AND status=$2`, userid, orders.PROCESSED)
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+// Количество наград, полученных пригласившим пользователем
+func (r *referralRepository) CountRewarded(ctx context.Context, referrerID string) (int, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM referrals WHERE referrer_id=$1 AND status=$2 AND referrer_bonus > 0`,
+		referrerID, referral.REWARDED)
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *referralRepository) Resolve(ctx context.Context, ref *referral.Referral) error {
+	_, err := transactor.FromContext(ctx, r.db).ExecContext(ctx, `UPDATE referrals
+	SET status=$1, order_num=$2, referrer_bonus=$3, referred_bonus=$4, rewarded_at=NOW()
+	WHERE id=$5`, ref.Status, ref.Order, ref.ReferrerBonus, ref.ReferredBonus, ref.ID)
+	r.log.Infoln("[RESOLVE REFERRAL]", ref.ID, ref.Status)
+	return err
+}
+
+func (r *referralRepository) GetStats(ctx context.Context, referrerID string) (*referral.Stats, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*),
+		COUNT(*) FILTER (WHERE status=$2),
+		COUNT(*) FILTER (WHERE status=$3),
+		COALESCE(SUM(referrer_bonus), 0)
+	FROM referrals WHERE referrer_id=$1`, referrerID, referral.PENDING, referral.REWARDED)
+
+	var s referral.Stats
+	err := row.Scan(&s.Invited, &s.Pending, &s.Rewarded, &s.Earned)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/referral"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+// Количество попыток выдать уникальный код
+const codeAttempts = 3
+
+type referralUsecase struct {
+	referralRepo ReferralRepo
+	balanceRepo  BalanceRepo
+	points       Points
+	transactor   Transactor
+	program      referral.Program
+	logger       logger.Logger
+}
+
+func New(rr ReferralRepo, br BalanceRepo, pts Points, t Transactor, p referral.Program, l logger.Logger) *referralUsecase {
+	return &referralUsecase{
+		referralRepo: rr,
+		balanceRepo:  br,
+		points:       pts,
+		transactor:   t,
+		program:      p,
+		logger:       l}
+}
+
+// Выдает код новому пользователю и, если указан код пригласившего, создает приглашение.
+// Вызывается в транзакции регистрации. Приглашение отклоняется, если приглашенный регистрируется
+// с IP пригласившего или другого его приглашенного, а также сверх MaxPerDay приглашений за сутки
+func (r *referralUsecase) Register(ctx context.Context, userid string, code string, ip string) error {
+	_, err := r.assignCode(ctx, userid)
+	if err != nil {
+		return err
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil
+	}
+
+	referrerID, err := r.referralRepo.GetUserByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	if referrerID == "" {
+		return referral.ErrInvalidCode
+	}
+	if referrerID == userid {
+		return referral.ErrSelfReferral
+	}
+
+	//блокировка пригласившего упорядочивает параллельные регистрации по его коду
+	referrerIP, err := r.referralRepo.LockReferrer(ctx, referrerID)
+	if err != nil {
+		return err
+	}
+
+	if ip != "" {
+		if ip == referrerIP {
+			r.logger.Infow("[REFERRAL SAME IP]", "referrer", referrerID, "ip", ip)
+			return referral.ErrSelfReferral
+		}
+
+		fromIP, err := r.referralRepo.CountFromIP(ctx, referrerID, ip)
+		if err != nil {
+			return err
+		}
+		if fromIP > 0 {
+			r.logger.Infow("[REFERRAL SAME IP]", "referrer", referrerID, "ip", ip)
+			return referral.ErrSelfReferral
+		}
+	}
+
+	if r.program.MaxPerDay > 0 {
+		recent, err := r.referralRepo.CountSince(ctx, referrerID, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return err
+		}
+		if recent >= r.program.MaxPerDay {
+			r.logger.Infow("[REFERRAL LIMIT]", "referrer", referrerID, "recent", recent)
+			return referral.ErrTooMany
+		}
+	}
+
+	return r.referralRepo.Create(ctx, referrerID, userid)
+}
+
+// Начисляет бонусы обеим сторонам, когда первый заказ приглашенного пользователя обработан.
+// Вызывается в транзакции обработки заказа. Если первый заказ без начисления или меньше минимального,
+// приглашение отклоняется. После MaxPerUser наград пригласивший бонус не получает
+func (r *referralUsecase) Reward(ctx context.Context, userid string, order string, accrual float64) error {
+	ref, err := r.referralRepo.GetPendingByReferred(ctx, userid)
+	if err != nil || ref == nil {
+		return err
+	}
+
+	processed, err := r.referralRepo.CountProcessedOrders(ctx, userid)
+	if err != nil {
+		return err
+	}
+
+	ref.Order = order
+
+	//первый заказ уже обработан без награды, ждать больше нечего
+	if processed > 1 || accrual <= 0 || accrual < r.program.MinAccrual {
+		ref.Status = referral.REJECTED
+		r.logger.Infow("[REFERRAL REJECTED]", "referral", ref.ID, "accrual", accrual)
+		return r.referralRepo.Resolve(ctx, ref)
+	}
+
+	ref.Status = referral.REWARDED
+	ref.ReferredBonus = r.program.ReferredBonus
+	ref.ReferrerBonus = r.program.ReferrerBonus
+
+	if r.program.MaxPerUser > 0 && ref.ReferrerBonus > 0 {
+		//без блокировки параллельные награды одного пригласившего превысят лимит
+		if _, err := r.referralRepo.LockReferrer(ctx, ref.ReferrerID); err != nil {
+			return err
+		}
+
+		rewarded, err := r.referralRepo.CountRewarded(ctx, ref.ReferrerID)
+		if err != nil {
+			return err
+		}
+		if rewarded >= r.program.MaxPerUser {
+			ref.ReferrerBonus = 0
+		}
+	}
+
+	if err := r.credit(ctx, ref.ReferredID, order, ref.ReferredBonus); err != nil {
+		return err
+	}
+	if err := r.credit(ctx, ref.ReferrerID, order, ref.ReferrerBonus); err != nil {
+		return err
+	}
+
+	r.logger.Infow("[REFERRAL REWARDED]", "referral", ref.ID, "referrer_bonus", ref.ReferrerBonus, "referred_bonus", ref.ReferredBonus)
+	return r.referralRepo.Resolve(ctx, ref)
+}
+
+func (r *referralUsecase) GetStats(ctx context.Context, userid string) (*referral.Stats, error) {
+	code, err := r.assignCode(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := r.referralRepo.GetStats(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Code = code
+	return stats, nil
+}
+
+func (r *referralUsecase) credit(ctx context.Context, userid string, order string, amount float64) error {
+	if amount <= 0 {
+		return nil
+	}
+
+	net, err := r.balanceRepo.Add(ctx, userid, &amount)
+	if err != nil {
+		return err
+	}
+
+	return r.points.Credit(ctx, userid, points.SourceReferral, order, amount, net)
+}
+
+// Возвращает код пользователя, выдавая новый, если его еще нет (пользователи, зарегистрированные до программы).
+// Коллизия кода откатывает только точку сохранения, после чего пробуем другой код
+func (r *referralUsecase) assignCode(ctx context.Context, userid string) (string, error) {
+	code, err := r.referralRepo.GetCode(ctx, userid)
+	if err != nil || code != "" {
+		return code, err
+	}
+
+	for attempt := 0; attempt < codeAttempts; attempt++ {
+		code, err = referral.GenerateCode()
+		if err != nil {
+			return "", err
+		}
+
+		err = r.transactor.Within(ctx, func(ctx context.Context) error {
+			return r.referralRepo.SetCode(ctx, userid, code)
+		})
+
+		if err == nil {
+			return r.referralRepo.GetCode(ctx, userid)
+		}
+		if !errors.Is(err, referral.ErrCodeExist) {
+			return "", err
+		}
+	}
+
+	return "", err
+}
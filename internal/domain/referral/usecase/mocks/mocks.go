@@ -0,0 +1,294 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/referral/usecase (interfaces: ReferralRepo,BalanceRepo,Points)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/referral/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/referral/usecase ReferralRepo,BalanceRepo,Points
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	points "github.com/benderr/gophermart/internal/domain/points"
+	referral "github.com/benderr/gophermart/internal/domain/referral"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReferralRepo is a mock of ReferralRepo interface.
+type MockReferralRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferralRepoMockRecorder
+}
+
+// MockReferralRepoMockRecorder is the mock recorder for MockReferralRepo.
+type MockReferralRepoMockRecorder struct {
+	mock *MockReferralRepo
+}
+
+// NewMockReferralRepo creates a new mock instance.
+func NewMockReferralRepo(ctrl *gomock.Controller) *MockReferralRepo {
+	mock := &MockReferralRepo{ctrl: ctrl}
+	mock.recorder = &MockReferralRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferralRepo) EXPECT() *MockReferralRepoMockRecorder {
+	return m.recorder
+}
+
+// CountFromIP mocks base method.
+func (m *MockReferralRepo) CountFromIP(arg0 context.Context, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFromIP", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFromIP indicates an expected call of CountFromIP.
+func (mr *MockReferralRepoMockRecorder) CountFromIP(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFromIP", reflect.TypeOf((*MockReferralRepo)(nil).CountFromIP), arg0, arg1, arg2)
+}
+
+// CountProcessedOrders mocks base method.
+func (m *MockReferralRepo) CountProcessedOrders(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountProcessedOrders", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountProcessedOrders indicates an expected call of CountProcessedOrders.
+func (mr *MockReferralRepoMockRecorder) CountProcessedOrders(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProcessedOrders", reflect.TypeOf((*MockReferralRepo)(nil).CountProcessedOrders), arg0, arg1)
+}
+
+// CountRewarded mocks base method.
+func (m *MockReferralRepo) CountRewarded(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRewarded", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRewarded indicates an expected call of CountRewarded.
+func (mr *MockReferralRepoMockRecorder) CountRewarded(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRewarded", reflect.TypeOf((*MockReferralRepo)(nil).CountRewarded), arg0, arg1)
+}
+
+// CountSince mocks base method.
+func (m *MockReferralRepo) CountSince(arg0 context.Context, arg1 string, arg2 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSince", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSince indicates an expected call of CountSince.
+func (mr *MockReferralRepoMockRecorder) CountSince(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSince", reflect.TypeOf((*MockReferralRepo)(nil).CountSince), arg0, arg1, arg2)
+}
+
+// Create mocks base method.
+func (m *MockReferralRepo) Create(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReferralRepoMockRecorder) Create(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReferralRepo)(nil).Create), arg0, arg1, arg2)
+}
+
+// GetCode mocks base method.
+func (m *MockReferralRepo) GetCode(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCode", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCode indicates an expected call of GetCode.
+func (mr *MockReferralRepoMockRecorder) GetCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCode", reflect.TypeOf((*MockReferralRepo)(nil).GetCode), arg0, arg1)
+}
+
+// GetPendingByReferred mocks base method.
+func (m *MockReferralRepo) GetPendingByReferred(arg0 context.Context, arg1 string) (*referral.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingByReferred", arg0, arg1)
+	ret0, _ := ret[0].(*referral.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingByReferred indicates an expected call of GetPendingByReferred.
+func (mr *MockReferralRepoMockRecorder) GetPendingByReferred(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingByReferred", reflect.TypeOf((*MockReferralRepo)(nil).GetPendingByReferred), arg0, arg1)
+}
+
+// GetStats mocks base method.
+func (m *MockReferralRepo) GetStats(arg0 context.Context, arg1 string) (*referral.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", arg0, arg1)
+	ret0, _ := ret[0].(*referral.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockReferralRepoMockRecorder) GetStats(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockReferralRepo)(nil).GetStats), arg0, arg1)
+}
+
+// GetUserByCode mocks base method.
+func (m *MockReferralRepo) GetUserByCode(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByCode", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByCode indicates an expected call of GetUserByCode.
+func (mr *MockReferralRepoMockRecorder) GetUserByCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByCode", reflect.TypeOf((*MockReferralRepo)(nil).GetUserByCode), arg0, arg1)
+}
+
+// LockReferrer mocks base method.
+func (m *MockReferralRepo) LockReferrer(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockReferrer", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockReferrer indicates an expected call of LockReferrer.
+func (mr *MockReferralRepoMockRecorder) LockReferrer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockReferrer", reflect.TypeOf((*MockReferralRepo)(nil).LockReferrer), arg0, arg1)
+}
+
+// Resolve mocks base method.
+func (m *MockReferralRepo) Resolve(arg0 context.Context, arg1 *referral.Referral) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockReferralRepoMockRecorder) Resolve(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockReferralRepo)(nil).Resolve), arg0, arg1)
+}
+
+// SetCode mocks base method.
+func (m *MockReferralRepo) SetCode(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCode indicates an expected call of SetCode.
+func (mr *MockReferralRepoMockRecorder) SetCode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCode", reflect.TypeOf((*MockReferralRepo)(nil).SetCode), arg0, arg1, arg2)
+}
+
+// MockBalanceRepo is a mock of BalanceRepo interface.
+type MockBalanceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceRepoMockRecorder
+}
+
+// MockBalanceRepoMockRecorder is the mock recorder for MockBalanceRepo.
+type MockBalanceRepoMockRecorder struct {
+	mock *MockBalanceRepo
+}
+
+// NewMockBalanceRepo creates a new mock instance.
+func NewMockBalanceRepo(ctrl *gomock.Controller) *MockBalanceRepo {
+	mock := &MockBalanceRepo{ctrl: ctrl}
+	mock.recorder = &MockBalanceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceRepo) EXPECT() *MockBalanceRepoMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockBalanceRepo) Add(arg0 context.Context, arg1 string, arg2 *float64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockBalanceRepoMockRecorder) Add(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBalanceRepo)(nil).Add), arg0, arg1, arg2)
+}
+
+// MockPoints is a mock of Points interface.
+type MockPoints struct {
+	ctrl     *gomock.Controller
+	recorder *MockPointsMockRecorder
+}
+
+// MockPointsMockRecorder is the mock recorder for MockPoints.
+type MockPointsMockRecorder struct {
+	mock *MockPoints
+}
+
+// NewMockPoints creates a new mock instance.
+func NewMockPoints(ctrl *gomock.Controller) *MockPoints {
+	mock := &MockPoints{ctrl: ctrl}
+	mock.recorder = &MockPointsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPoints) EXPECT() *MockPointsMockRecorder {
+	return m.recorder
+}
+
+// Credit mocks base method.
+func (m *MockPoints) Credit(arg0 context.Context, arg1 string, arg2 points.Source, arg3 string, arg4, arg5 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credit", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockPointsMockRecorder) Credit(arg0, arg1, arg2, arg3, arg4, arg5 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockPoints)(nil).Credit), arg0, arg1, arg2, arg3, arg4, arg5)
+}
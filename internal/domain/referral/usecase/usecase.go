@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/referral"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type ReferralRepo interface {
+	GetCode(ctx context.Context, userid string) (string, error)
+	SetCode(ctx context.Context, userid string, code string) error
+	GetUserByCode(ctx context.Context, code string) (string, error)
+	LockReferrer(ctx context.Context, referrerID string) (string, error)
+	CountSince(ctx context.Context, referrerID string, since time.Time) (int, error)
+	CountFromIP(ctx context.Context, referrerID string, ip string) (int, error)
+	Create(ctx context.Context, referrerID string, referredID string) error
+	GetPendingByReferred(ctx context.Context, userid string) (*referral.Referral, error)
+	CountProcessedOrders(ctx context.Context, userid string) (int, error)
+	CountRewarded(ctx context.Context, referrerID string) (int, error)
+	Resolve(ctx context.Context, ref *referral.Referral) error
+	GetStats(ctx context.Context, referrerID string) (*referral.Stats, error)
+}
+
+type BalanceRepo interface {
+	//возвращает сумму, зачисленную на баланс после погашения долга
+	Add(ctx context.Context, userid string, balance *float64) (float64, error)
+}
+
+type Points interface {
+	Credit(ctx context.Context, userid string, source points.Source, order string, credited float64, net float64) error
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
@@ -0,0 +1,185 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/referral"
+	"github.com/benderr/gophermart/internal/domain/referral/usecase"
+	"github.com/benderr/gophermart/internal/domain/referral/usecase/mocks"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReward(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReferralRepo := mocks.NewMockReferralRepo(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	mockPoints := mocks.NewMockPoints(ctrl)
+	mockTransactor := mocktransactor.New()
+	mockLogger := mocklogger.New()
+	program := referral.Program{ReferrerBonus: 100, ReferredBonus: 50, MaxPerUser: 3, MinAccrual: 10}
+	referralUsecase := usecase.New(mockReferralRepo, mockBalanceRepo, mockPoints, mockTransactor, program, mockLogger)
+
+	t.Run("Both parties rewarded", func(t *testing.T) {
+		ref := &referral.Referral{ID: "ref", ReferrerID: "referrer", ReferredID: "referred", Status: referral.PENDING}
+
+		mockReferralRepo.EXPECT().GetPendingByReferred(gomock.Any(), "referred").Return(ref, nil)
+		mockReferralRepo.EXPECT().CountProcessedOrders(gomock.Any(), "referred").Return(1, nil)
+		mockReferralRepo.EXPECT().LockReferrer(gomock.Any(), "referrer").Return("", nil)
+		mockReferralRepo.EXPECT().CountRewarded(gomock.Any(), "referrer").Return(0, nil)
+		//часть бонуса приглашенного ушла на погашение долга, партия создается только на остаток
+		mockBalanceRepo.EXPECT().Add(gomock.Any(), "referred", gomock.Any()).Return(30.0, nil)
+		mockPoints.EXPECT().Credit(gomock.Any(), "referred", points.SourceReferral, "123", 50.0, 30.0).Return(nil)
+		mockBalanceRepo.EXPECT().Add(gomock.Any(), "referrer", gomock.Any()).Return(100.0, nil)
+		mockPoints.EXPECT().Credit(gomock.Any(), "referrer", points.SourceReferral, "123", 100.0, 100.0).Return(nil)
+		mockReferralRepo.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := referralUsecase.Reward(context.Background(), "referred", "123", 20)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, referral.REWARDED, ref.Status)
+		}
+	})
+
+	t.Run("Referrer reached cap", func(t *testing.T) {
+		ref := &referral.Referral{ID: "ref", ReferrerID: "referrer", ReferredID: "referred", Status: referral.PENDING}
+
+		mockReferralRepo.EXPECT().GetPendingByReferred(gomock.Any(), "referred").Return(ref, nil)
+		mockReferralRepo.EXPECT().CountProcessedOrders(gomock.Any(), "referred").Return(1, nil)
+		mockReferralRepo.EXPECT().LockReferrer(gomock.Any(), "referrer").Return("", nil)
+		mockReferralRepo.EXPECT().CountRewarded(gomock.Any(), "referrer").Return(3, nil)
+		mockBalanceRepo.EXPECT().Add(gomock.Any(), "referred", gomock.Any()).Return(50.0, nil)
+		mockPoints.EXPECT().Credit(gomock.Any(), "referred", points.SourceReferral, "123", 50.0, 50.0).Return(nil)
+		mockReferralRepo.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := referralUsecase.Reward(context.Background(), "referred", "123", 20)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0.0, ref.ReferrerBonus)
+		}
+	})
+
+	t.Run("First order below minimum", func(t *testing.T) {
+		ref := &referral.Referral{ID: "ref", ReferrerID: "referrer", ReferredID: "referred", Status: referral.PENDING}
+
+		mockReferralRepo.EXPECT().GetPendingByReferred(gomock.Any(), "referred").Return(ref, nil)
+		mockReferralRepo.EXPECT().CountProcessedOrders(gomock.Any(), "referred").Return(1, nil)
+		mockReferralRepo.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := referralUsecase.Reward(context.Background(), "referred", "123", 5)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, referral.REJECTED, ref.Status)
+		}
+	})
+
+	t.Run("First order without accrual", func(t *testing.T) {
+		ref := &referral.Referral{ID: "ref", ReferrerID: "referrer", ReferredID: "referred", Status: referral.PENDING}
+
+		mockReferralRepo.EXPECT().GetPendingByReferred(gomock.Any(), "referred").Return(ref, nil)
+		mockReferralRepo.EXPECT().CountProcessedOrders(gomock.Any(), "referred").Return(1, nil)
+		mockReferralRepo.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := referralUsecase.Reward(context.Background(), "referred", "123", 0)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, referral.REJECTED, ref.Status)
+		}
+	})
+
+	t.Run("Not invited", func(t *testing.T) {
+		mockReferralRepo.EXPECT().GetPendingByReferred(gomock.Any(), "user").Return(nil, nil)
+
+		assert.NoError(t, referralUsecase.Reward(context.Background(), "user", "123", 20))
+	})
+}
+
+func TestRegister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReferralRepo := mocks.NewMockReferralRepo(ctrl)
+	referralUsecase := usecase.New(mockReferralRepo, mocks.NewMockBalanceRepo(ctrl), mocks.NewMockPoints(ctrl), mocktransactor.New(), referral.Program{}, mocklogger.New())
+
+	t.Run("Unknown code", func(t *testing.T) {
+		mockReferralRepo.EXPECT().GetCode(gomock.Any(), "user").Return("", nil)
+		mockReferralRepo.EXPECT().SetCode(gomock.Any(), "user", gomock.Any()).Return(nil)
+		mockReferralRepo.EXPECT().GetCode(gomock.Any(), "user").Return("OWNCODE1", nil)
+		mockReferralRepo.EXPECT().GetUserByCode(gomock.Any(), "ABCDEFGH").Return("", nil)
+
+		err := referralUsecase.Register(context.Background(), "user", " abcdefgh ", "10.0.0.1")
+
+		assert.ErrorIs(t, err, referral.ErrInvalidCode)
+	})
+
+	t.Run("Code collision retried", func(t *testing.T) {
+		mockReferralRepo.EXPECT().GetCode(gomock.Any(), "user").Return("", nil)
+		gomock.InOrder(
+			mockReferralRepo.EXPECT().SetCode(gomock.Any(), "user", gomock.Any()).Return(referral.ErrCodeExist),
+			mockReferralRepo.EXPECT().SetCode(gomock.Any(), "user", gomock.Any()).Return(nil),
+		)
+		mockReferralRepo.EXPECT().GetCode(gomock.Any(), "user").Return("OWNCODE1", nil)
+
+		assert.NoError(t, referralUsecase.Register(context.Background(), "user", "", "10.0.0.1"))
+	})
+}
+
+func TestRegisterAbuse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReferralRepo := mocks.NewMockReferralRepo(ctrl)
+	program := referral.Program{MaxPerDay: 5}
+	referralUsecase := usecase.New(mockReferralRepo, mocks.NewMockBalanceRepo(ctrl), mocks.NewMockPoints(ctrl), mocktransactor.New(), program, mocklogger.New())
+
+	expectCode := func() {
+		mockReferralRepo.EXPECT().GetCode(gomock.Any(), "user").Return("OWNCODE1", nil)
+		mockReferralRepo.EXPECT().GetUserByCode(gomock.Any(), "ABCDEFGH").Return("referrer", nil)
+	}
+
+	t.Run("Same IP as referrer", func(t *testing.T) {
+		expectCode()
+		mockReferralRepo.EXPECT().LockReferrer(gomock.Any(), "referrer").Return("10.0.0.1", nil)
+
+		err := referralUsecase.Register(context.Background(), "user", "ABCDEFGH", "10.0.0.1")
+
+		assert.ErrorIs(t, err, referral.ErrSelfReferral)
+	})
+
+	t.Run("Same IP as another invited", func(t *testing.T) {
+		expectCode()
+		mockReferralRepo.EXPECT().LockReferrer(gomock.Any(), "referrer").Return("10.0.0.2", nil)
+		mockReferralRepo.EXPECT().CountFromIP(gomock.Any(), "referrer", "10.0.0.1").Return(1, nil)
+
+		err := referralUsecase.Register(context.Background(), "user", "ABCDEFGH", "10.0.0.1")
+
+		assert.ErrorIs(t, err, referral.ErrSelfReferral)
+	})
+
+	t.Run("Daily limit reached", func(t *testing.T) {
+		expectCode()
+		mockReferralRepo.EXPECT().LockReferrer(gomock.Any(), "referrer").Return("10.0.0.2", nil)
+		mockReferralRepo.EXPECT().CountFromIP(gomock.Any(), "referrer", "10.0.0.1").Return(0, nil)
+		mockReferralRepo.EXPECT().CountSince(gomock.Any(), "referrer", gomock.Any()).Return(5, nil)
+
+		err := referralUsecase.Register(context.Background(), "user", "ABCDEFGH", "10.0.0.1")
+
+		assert.ErrorIs(t, err, referral.ErrTooMany)
+	})
+
+	t.Run("Referral created", func(t *testing.T) {
+		expectCode()
+		mockReferralRepo.EXPECT().LockReferrer(gomock.Any(), "referrer").Return("10.0.0.2", nil)
+		mockReferralRepo.EXPECT().CountFromIP(gomock.Any(), "referrer", "10.0.0.1").Return(0, nil)
+		mockReferralRepo.EXPECT().CountSince(gomock.Any(), "referrer", gomock.Any()).Return(4, nil)
+		mockReferralRepo.EXPECT().Create(gomock.Any(), "referrer", "user").Return(nil)
+
+		assert.NoError(t, referralUsecase.Register(context.Background(), "user", "ABCDEFGH", "10.0.0.1"))
+	})
+}
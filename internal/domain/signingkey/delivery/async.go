@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type RotateUsecase interface {
+	Rotate(ctx context.Context) error
+}
+
+type rotateTask struct {
+	keys     RotateUsecase
+	interval time.Duration
+	logger   logger.Logger
+}
+
+func NewRotateTask(ku RotateUsecase, interval time.Duration, logger logger.Logger) *rotateTask {
+	return &rotateTask{
+		keys:     ku,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Периодически проверяет срок ключа подписи и перечитывает набор ключей, пока не будет отменен ctx
+func (r *rotateTask) Run(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.keys.Rotate(ctx); err != nil {
+					r.logger.Errorln("rotate signing keys error", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,32 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	"github.com/labstack/echo/v4"
+)
+
+type KeyUsecase interface {
+	JWKS() signingkey.JWKS
+}
+
+type keyHandler struct {
+	KeyUsecase
+}
+
+// Открытые ключи для проверки токенов другими сервисами
+func NewKeyHandlers(group *echo.Group, ku KeyUsecase) {
+	h := &keyHandler{
+		KeyUsecase: ku,
+	}
+
+	group.GET("/.well-known/jwks.json", h.JWKSHandler)
+}
+
+// Ротация сразу начинает подписывать новым ключом, поэтому набор ключей не кэшируется:
+// иначе проверяющий сервис до истечения кэша отклонял бы токены с новым kid
+func (k *keyHandler) JWKSHandler(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+	return c.JSON(http.StatusOK, k.JWKS())
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type keyRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *keyRepository {
+	return &keyRepository{db: db, log: log}
+}
+
+// Блокировка ротации до конца транзакции, чтобы несколько экземпляров не создали ключи одновременно
+func (k *keyRepository) Lock(ctx context.Context) error {
+	_, err := transactor.FromContext(ctx, k.db).ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('signing_keys'))`)
+	return err
+}
+
+// Ключи, пригодные для проверки подписи, новые первыми. Для каждого ключа отмечается,
+// что с момента создания прошло больше rotation (0 - ротация по сроку отключена)
+func (k *keyRepository) GetValid(ctx context.Context, rotation time.Duration) ([]signingkey.Key, error) {
+	list := make([]signingkey.Key, 0)
+
+	rows, err := transactor.FromContext(ctx, k.db).QueryContext(ctx, `SELECT id, algorithm, private_key, created_at, retires_at,
+		$1 > 0 AND created_at <= NOW() - $1 * interval '1 second'
+	FROM signing_keys
+	WHERE retires_at IS NULL OR retires_at > NOW()
+	ORDER BY created_at DESC`, rotation.Seconds())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var alg signingkey.Algorithm
+		var der []byte
+		var createdAt time.Time
+		var retiresAt sql.NullTime
+		var due bool
+
+		if err := rows.Scan(&id, &alg, &der, &createdAt, &retiresAt, &due); err != nil {
+			return nil, err
+		}
+
+		key, err := signingkey.Unmarshal(id, alg, der)
+		if err != nil {
+			return nil, err
+		}
+
+		key.CreatedAt = createdAt
+		key.RotationDue = due
+		if retiresAt.Valid {
+			key.RetiresAt = &retiresAt.Time
+		}
+
+		list = append(list, *key)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (k *keyRepository) Create(ctx context.Context, key *signingkey.Key) error {
+	der, err := key.Marshal()
+	if err != nil {
+		return err
+	}
+
+	_, err = transactor.FromContext(ctx, k.db).ExecContext(ctx, `INSERT INTO signing_keys (id, algorithm, private_key) VALUES($1, $2, $3)`, key.ID, key.Algorithm, der)
+	k.log.Infoln("[CREATE SIGNING KEY]", key.ID, key.Algorithm)
+	return err
+}
+
+// Выводит из подписи все ключи, кроме текущего. Проверять ими подписи можно еще retention
+func (k *keyRepository) Retire(ctx context.Context, currentID string, retention time.Duration) error {
+	_, err := transactor.FromContext(ctx, k.db).ExecContext(ctx, `UPDATE signing_keys SET retires_at = NOW() + $2 * interval '1 second'
+	WHERE id <> $1 AND retires_at IS NULL`, currentID, retention.Seconds())
+	return err
+}
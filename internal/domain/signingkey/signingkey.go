@@ -0,0 +1,140 @@
+package signingkey
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Algorithm string
+
+const (
+	HS256 Algorithm = "HS256"
+	RS256 Algorithm = "RS256"
+	EdDSA Algorithm = "EdDSA"
+)
+
+// Размер ключа RSA в битах
+const rsaBits = 2048
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown signing algorithm")
+	ErrNoKey            = errors.New("no active signing key")
+)
+
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch a := Algorithm(s); a {
+	case HS256, RS256, EdDSA:
+		return a, nil
+	}
+	return "", ErrUnknownAlgorithm
+}
+
+// Асимметричный ключ подписи. Новый ключ подписывает токены, прежние после ротации
+// остаются доступными для проверки до RetiresAt
+type Key struct {
+	ID        string
+	Algorithm Algorithm
+	Private   crypto.Signer
+	CreatedAt time.Time
+	RetiresAt *time.Time
+	//ключ подписывает токены дольше периода ротации
+	RotationDue bool
+}
+
+func Generate(alg Algorithm) (*Key, error) {
+	var private crypto.Signer
+	var err error
+
+	switch alg {
+	case RS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaBits)
+	case EdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &Key{ID: hex.EncodeToString(id), Algorithm: alg, Private: private}, nil
+}
+
+func (k *Key) Method() jwt.SigningMethod {
+	if k.Algorithm == EdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+func (k *Key) Public() crypto.PublicKey {
+	return k.Private.Public()
+}
+
+// Закрытый ключ в PKCS #8 для хранения
+func (k *Key) Marshal() ([]byte, error) {
+	return x509.MarshalPKCS8PrivateKey(k.Private)
+}
+
+func Unmarshal(id string, alg Algorithm, der []byte) (*Key, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	return &Key{ID: id, Algorithm: alg, Private: private}, nil
+}
+
+// Открытый ключ в формате JWK (RFC 7517, RFC 8037)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k *Key) JWK() JWK {
+	jwk := JWK{Use: "sig", Alg: string(k.Algorithm), Kid: k.ID}
+
+	switch pub := k.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
@@ -0,0 +1,68 @@
+package signingkey_test
+
+import (
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, alg := range []signingkey.Algorithm{signingkey.RS256, signingkey.EdDSA} {
+		t.Run(string(alg), func(t *testing.T) {
+			key, err := signingkey.Generate(alg)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			der, err := key.Marshal()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			restored, err := signingkey.Unmarshal(key.ID, alg, der)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			signed, err := jwt.NewWithClaims(key.Method(), jwt.MapClaims{"userid": "testuserid"}).SignedString(key.Private)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = jwt.Parse(signed, func(*jwt.Token) (interface{}, error) {
+				return restored.Public(), nil
+			}, jwt.WithValidMethods([]string{restored.Method().Alg()}))
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("HS256 is not generated", func(t *testing.T) {
+		_, err := signingkey.Generate(signingkey.HS256)
+		assert.Equal(t, signingkey.ErrUnknownAlgorithm, err)
+	})
+}
+
+func TestJWK(t *testing.T) {
+	t.Run("Ed25519", func(t *testing.T) {
+		key, err := signingkey.Generate(signingkey.EdDSA)
+		if assert.NoError(t, err) {
+			jwk := key.JWK()
+			assert.Equal(t, "OKP", jwk.Kty)
+			assert.Equal(t, "Ed25519", jwk.Crv)
+			assert.Equal(t, key.ID, jwk.Kid)
+			assert.Len(t, jwk.X, 43)
+		}
+	})
+
+	t.Run("RSA", func(t *testing.T) {
+		key, err := signingkey.Generate(signingkey.RS256)
+		if assert.NoError(t, err) {
+			jwk := key.JWK()
+			assert.Equal(t, "RSA", jwk.Kty)
+			assert.Equal(t, "AQAB", jwk.E)
+			assert.Equal(t, "RS256", jwk.Alg)
+		}
+	})
+}
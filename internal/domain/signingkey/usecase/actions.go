@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+// Неизвестный kid перечитывает ключи из базы не чаще этого интервала
+const reloadInterval = 5 * time.Second
+
+// Сколько ждать базу при перечитывании ключей во время проверки токена
+const reloadTimeout = 2 * time.Second
+
+type keyUsecase struct {
+	keyRepo    KeyRepo
+	transactor Transactor
+	algorithm  signingkey.Algorithm
+	rotation   time.Duration
+	retention  time.Duration
+	logger     logger.Logger
+
+	mu   sync.RWMutex
+	keys []signingkey.Key
+
+	reloadMu sync.Mutex
+	reloaded time.Time
+}
+
+// Ключи хранятся в базе, чтобы все экземпляры сервиса подписывали и проверяли одними ключами.
+// retention - сколько выведенный из подписи ключ еще принимается, не меньше срока жизни токена доступа
+func New(kr KeyRepo, t Transactor, alg signingkey.Algorithm, rotation, retention time.Duration, l logger.Logger) *keyUsecase {
+	return &keyUsecase{
+		keyRepo:    kr,
+		transactor: t,
+		algorithm:  alg,
+		rotation:   rotation,
+		retention:  retention,
+		logger:     l,
+	}
+}
+
+// Создает новый ключ, если текущего нет, его срок подошел или сменился алгоритм,
+// и перечитывает набор ключей. Заодно подхватывает ключи, созданные другими экземплярами
+func (k *keyUsecase) Rotate(ctx context.Context) error {
+	var keys []signingkey.Key
+
+	err := k.transactor.Within(ctx, func(ctx context.Context) error {
+		if err := k.keyRepo.Lock(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		keys, err = k.keyRepo.GetValid(ctx, k.rotation)
+		if err != nil {
+			return err
+		}
+
+		if current := signingKey(keys); current != nil && current.Algorithm == k.algorithm && !current.RotationDue {
+			return nil
+		}
+
+		key, err := signingkey.Generate(k.algorithm)
+		if err != nil {
+			return err
+		}
+
+		if err := k.keyRepo.Create(ctx, key); err != nil {
+			return err
+		}
+
+		if err := k.keyRepo.Retire(ctx, key.ID, k.retention); err != nil {
+			return err
+		}
+
+		k.logger.Infoln("[ROTATE SIGNING KEY]", key.ID, key.Algorithm)
+
+		keys, err = k.keyRepo.GetValid(ctx, k.rotation)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+
+	return nil
+}
+
+// Ключ для подписи новых токенов
+func (k *keyUsecase) SigningKey() (*signingkey.Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if key := signingKey(k.keys); key != nil {
+		return key, nil
+	}
+	return nil, signingkey.ErrNoKey
+}
+
+// Ключ для проверки подписи по kid. Другой экземпляр начинает подписывать новым ключом сразу после ротации,
+// поэтому неизвестный kid перечитывает набор ключей из базы, не дожидаясь очередной ротации
+func (k *keyUsecase) Key(kid string) (*signingkey.Key, bool) {
+	if key, ok := k.find(kid); ok {
+		return key, true
+	}
+
+	k.reload()
+	return k.find(kid)
+}
+
+// Перечитывает ключи из базы не чаще reloadInterval, чтобы токены с выдуманным kid не нагружали базу
+func (k *keyUsecase) reload() {
+	k.reloadMu.Lock()
+	defer k.reloadMu.Unlock()
+
+	if time.Since(k.reloaded) < reloadInterval {
+		return
+	}
+	k.reloaded = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+	defer cancel()
+
+	keys, err := k.keyRepo.GetValid(ctx, k.rotation)
+	if err != nil {
+		k.logger.Errorln("reload signing keys error", err)
+		return
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+}
+
+func (k *keyUsecase) find(kid string) (*signingkey.Key, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for i := range k.keys {
+		if k.keys[i].ID == kid {
+			return &k.keys[i], true
+		}
+	}
+	return nil, false
+}
+
+func (k *keyUsecase) JWKS() signingkey.JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := signingkey.JWKS{Keys: make([]signingkey.JWK, 0, len(k.keys))}
+	for i := range k.keys {
+		set.Keys = append(set.Keys, k.keys[i].JWK())
+	}
+	return set
+}
+
+func signingKey(keys []signingkey.Key) *signingkey.Key {
+	for i := range keys {
+		if keys[i].RetiresAt == nil {
+			return &keys[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/signingkey/usecase (interfaces: KeyRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/signingkey/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/signingkey/usecase KeyRepo
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	signingkey "github.com/benderr/gophermart/internal/domain/signingkey"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKeyRepo is a mock of KeyRepo interface.
+type MockKeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyRepoMockRecorder
+}
+
+// MockKeyRepoMockRecorder is the mock recorder for MockKeyRepo.
+type MockKeyRepoMockRecorder struct {
+	mock *MockKeyRepo
+}
+
+// NewMockKeyRepo creates a new mock instance.
+func NewMockKeyRepo(ctrl *gomock.Controller) *MockKeyRepo {
+	mock := &MockKeyRepo{ctrl: ctrl}
+	mock.recorder = &MockKeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyRepo) EXPECT() *MockKeyRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockKeyRepo) Create(arg0 context.Context, arg1 *signingkey.Key) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockKeyRepoMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockKeyRepo)(nil).Create), arg0, arg1)
+}
+
+// GetValid mocks base method.
+func (m *MockKeyRepo) GetValid(arg0 context.Context, arg1 time.Duration) ([]signingkey.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValid", arg0, arg1)
+	ret0, _ := ret[0].([]signingkey.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValid indicates an expected call of GetValid.
+func (mr *MockKeyRepoMockRecorder) GetValid(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValid", reflect.TypeOf((*MockKeyRepo)(nil).GetValid), arg0, arg1)
+}
+
+// Lock mocks base method.
+func (m *MockKeyRepo) Lock(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockKeyRepoMockRecorder) Lock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockKeyRepo)(nil).Lock), arg0)
+}
+
+// Retire mocks base method.
+func (m *MockKeyRepo) Retire(arg0 context.Context, arg1 string, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retire", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retire indicates an expected call of Retire.
+func (mr *MockKeyRepoMockRecorder) Retire(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retire", reflect.TypeOf((*MockKeyRepo)(nil).Retire), arg0, arg1, arg2)
+}
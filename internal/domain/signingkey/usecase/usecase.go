@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type KeyRepo interface {
+	Lock(ctx context.Context) error
+	GetValid(ctx context.Context, rotation time.Duration) ([]signingkey.Key, error)
+	Create(ctx context.Context, key *signingkey.Key) error
+	Retire(ctx context.Context, currentID string, retention time.Duration) error
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
@@ -0,0 +1,44 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	"github.com/benderr/gophermart/internal/domain/signingkey/usecase"
+	"github.com/benderr/gophermart/internal/domain/signingkey/usecase/mocks"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyRepo := mocks.NewMockKeyRepo(ctrl)
+	keyUsecase := usecase.New(mockKeyRepo, mocktransactor.New(), signingkey.EdDSA, time.Hour, time.Hour, mocklogger.New())
+
+	key, err := signingkey.Generate(signingkey.EdDSA)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("Unknown kid reloads keys", func(t *testing.T) {
+		//ключ создан другим экземпляром после последней ротации этого
+		mockKeyRepo.EXPECT().GetValid(gomock.Any(), time.Hour).Return([]signingkey.Key{*key}, nil)
+
+		found, ok := keyUsecase.Key(key.ID)
+
+		if assert.True(t, ok) {
+			assert.Equal(t, key.ID, found.ID)
+		}
+	})
+
+	t.Run("Reload is rate limited", func(t *testing.T) {
+		_, ok := keyUsecase.Key("unknown")
+
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,58 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/tier"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type TierUsecase interface {
+	GetStatus(ctx context.Context, userid string) (*tier.Status, error)
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type tierHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	TierUsecase
+}
+
+func NewTierHandlers(group *echo.Group, tu TierUsecase, session SessionManager, logger logger.Logger) {
+	h := &tierHandler{
+		TierUsecase: tu,
+		session:     session,
+		logger:      logger,
+	}
+
+	g := group.Group("/api/user")
+
+	g.GET("/tier", h.GetTierHandler)
+}
+
+func (t *tierHandler) GetTierHandler(c echo.Context) error {
+	userid, err := t.session.GetUserID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	st, err := t.GetStatus(c.Request().Context(), userid)
+
+	if err != nil {
+		if errors.Is(err, tier.ErrDisabled) {
+			return c.JSON(http.StatusNotFound, httputils.Error("tiers disabled"))
+		}
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, st)
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/orders"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type tierRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *tierRepository {
+	return &tierRepository{db: db, log: log}
+}
+
+func (t *tierRepository) Save(ctx context.Context, userid string, name string, progress float64) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `INSERT INTO user_tiers (user_id, tier, progress, updated_at)
+	VALUES($1, $2, $3, NOW())
+	ON CONFLICT (user_id)
+	DO UPDATE SET tier=$2, progress=$3, updated_at=NOW()`, userid, name, progress)
+	return err
+}
+
+// Сумма начислений по обработанным заказам за все время
+func (t *tierRepository) GetLifetimeAccrued(ctx context.Context, userid string) (float64, error) {
+	row := transactor.FromContext(ctx, t.db).QueryRowContext(ctx, `SELECT COALESCE(SUM(accrual), 0) FROM orders WHERE user_id=$1 AND status=$2`, userid, orders.PROCESSED)
+	var sum float64
+	err := row.Scan(&sum)
+	return sum, err
+}
+
+// Сумма списаний за последние window
+func (t *tierRepository) GetSpent(ctx context.Context, userid string, window time.Duration) (float64, error) {
+	row := transactor.FromContext(ctx, t.db).QueryRowContext(ctx, `SELECT COALESCE(SUM(sum), 0) FROM withdrawals WHERE user_id=$1 AND processed_at >= NOW() - $2 * interval '1 second'`, userid, window.Seconds())
+	var sum float64
+	err := row.Scan(&sum)
+	return sum, err
+}
@@ -0,0 +1,129 @@
+package tier
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// По какому показателю считается уровень пользователя
+type Basis string
+
+const (
+	//сумма всех начислений по заказам
+	BasisLifetime Basis = "lifetime"
+	//сумма списаний за скользящее окно
+	BasisSpend Basis = "spend"
+)
+
+type Tier struct {
+	Name       string
+	Threshold  float64
+	Multiplier float64
+}
+
+// Уровни, отсортированные по возрастанию порога
+type Tiers []Tier
+
+// Текущий уровень пользователя и прогресс до следующего
+type Status struct {
+	Tier          string   `json:"tier"`
+	Multiplier    float64  `json:"multiplier"`
+	Basis         Basis    `json:"basis"`
+	Progress      float64  `json:"progress"`
+	NextTier      string   `json:"next_tier,omitempty"`
+	NextThreshold *float64 `json:"next_threshold,omitempty"`
+	Remaining     *float64 `json:"remaining,omitempty"`
+}
+
+var (
+	ErrInvalidConfig = errors.New("invalid tiers config")
+	ErrDisabled      = errors.New("tiers disabled")
+)
+
+// Разбирает показатель уровня, пустая строка - BasisLifetime
+func ParseBasis(s string) (Basis, error) {
+	switch b := Basis(strings.TrimSpace(s)); b {
+	case "":
+		return BasisLifetime, nil
+	case BasisLifetime, BasisSpend:
+		return b, nil
+	default:
+		return "", fmt.Errorf("%w: basis %q", ErrInvalidConfig, s)
+	}
+}
+
+// Разбирает описание уровней вида "Bronze:0:1,Silver:1000:1.25,Gold:5000:1.5"
+// (название:порог:множитель)
+func Parse(s string) (Tiers, error) {
+	tiers := make(Tiers, 0)
+	if len(strings.TrimSpace(s)) == 0 {
+		return tiers, nil
+	}
+
+	for _, item := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 3 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidConfig, item)
+		}
+
+		threshold, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("%w: threshold %q", ErrInvalidConfig, parts[1])
+		}
+
+		multiplier, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || multiplier <= 0 {
+			return nil, fmt.Errorf("%w: multiplier %q", ErrInvalidConfig, parts[2])
+		}
+
+		tiers = append(tiers, Tier{Name: parts[0], Threshold: threshold, Multiplier: multiplier})
+	}
+
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].Threshold < tiers[j].Threshold })
+	return tiers, nil
+}
+
+// Находит уровень для значения progress и следующий за ним уровень (nil, если их нет)
+func (t Tiers) Resolve(progress float64) (current *Tier, next *Tier) {
+	for i := range t {
+		if progress >= t[i].Threshold {
+			current = &t[i]
+			continue
+		}
+		next = &t[i]
+		break
+	}
+	return current, next
+}
+
+func (t Tiers) Find(name string) *Tier {
+	for i := range t {
+		if t[i].Name == name {
+			return &t[i]
+		}
+	}
+	return nil
+}
+
+func (t Tiers) Status(basis Basis, progress float64) *Status {
+	st := &Status{Basis: basis, Progress: progress, Multiplier: 1}
+
+	current, next := t.Resolve(progress)
+	if current != nil {
+		st.Tier = current.Name
+		st.Multiplier = current.Multiplier
+	}
+
+	if next != nil {
+		threshold := next.Threshold
+		remaining := next.Threshold - progress
+		st.NextTier = next.Name
+		st.NextThreshold = &threshold
+		st.Remaining = &remaining
+	}
+
+	return st
+}
@@ -0,0 +1,81 @@
+package tier_test
+
+import (
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/tier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Parse sorted by threshold", func(t *testing.T) {
+		tiers, err := tier.Parse("Gold:5000:1.5, Bronze:0:1,Silver:1000:1.25")
+
+		if assert.NoError(t, err) && assert.Len(t, tiers, 3) {
+			assert.Equal(t, "Bronze", tiers[0].Name)
+			assert.Equal(t, "Silver", tiers[1].Name)
+			assert.Equal(t, 1.25, tiers[1].Multiplier)
+			assert.Equal(t, "Gold", tiers[2].Name)
+		}
+	})
+
+	t.Run("Parse empty", func(t *testing.T) {
+		tiers, err := tier.Parse("")
+
+		assert.NoError(t, err)
+		assert.Empty(t, tiers)
+	})
+
+	t.Run("Parse invalid", func(t *testing.T) {
+		for _, s := range []string{"Bronze:0", "Bronze:x:1", "Bronze:0:0", ":0:1"} {
+			_, err := tier.Parse(s)
+			assert.ErrorIs(t, err, tier.ErrInvalidConfig, s)
+		}
+	})
+}
+
+func TestParseBasis(t *testing.T) {
+	basis, err := tier.ParseBasis("")
+	if assert.NoError(t, err) {
+		assert.Equal(t, tier.BasisLifetime, basis)
+	}
+
+	basis, err = tier.ParseBasis("spend")
+	if assert.NoError(t, err) {
+		assert.Equal(t, tier.BasisSpend, basis)
+	}
+
+	_, err = tier.ParseBasis("spent")
+	assert.ErrorIs(t, err, tier.ErrInvalidConfig)
+}
+
+func TestStatus(t *testing.T) {
+	tiers, _ := tier.Parse("Bronze:0:1,Silver:1000:1.25,Gold:5000:1.5")
+
+	t.Run("Middle tier", func(t *testing.T) {
+		st := tiers.Status(tier.BasisLifetime, 1200)
+
+		assert.Equal(t, "Silver", st.Tier)
+		assert.Equal(t, 1.25, st.Multiplier)
+		assert.Equal(t, "Gold", st.NextTier)
+		if assert.NotNil(t, st.Remaining) {
+			assert.Equal(t, float64(3800), *st.Remaining)
+		}
+	})
+
+	t.Run("Top tier", func(t *testing.T) {
+		st := tiers.Status(tier.BasisLifetime, 9000)
+
+		assert.Equal(t, "Gold", st.Tier)
+		assert.Empty(t, st.NextTier)
+		assert.Nil(t, st.Remaining)
+	})
+
+	t.Run("Below lowest tier", func(t *testing.T) {
+		st := tier.Tiers{{Name: "Silver", Threshold: 100, Multiplier: 2}}.Status(tier.BasisSpend, 10)
+
+		assert.Empty(t, st.Tier)
+		assert.Equal(t, float64(1), st.Multiplier)
+		assert.Equal(t, "Silver", st.NextTier)
+	})
+}
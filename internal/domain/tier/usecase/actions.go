@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/tier"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type tierUsecase struct {
+	tierRepo TierRepo
+	tiers    tier.Tiers
+	basis    tier.Basis
+	window   time.Duration
+	logger   logger.Logger
+}
+
+func New(tr TierRepo, tiers tier.Tiers, basis tier.Basis, window time.Duration, l logger.Logger) *tierUsecase {
+	return &tierUsecase{
+		tierRepo: tr,
+		tiers:    tiers,
+		basis:    basis,
+		window:   window,
+		logger:   l}
+}
+
+// Множитель начислений по текущему уровню пользователя. Уровень пересчитывается перед чтением:
+// при базе по тратам за период сохраненный уровень устаревает и без начислений
+func (t *tierUsecase) Multiplier(ctx context.Context, userid string) (float64, error) {
+	st, err := t.Recalculate(ctx, userid)
+	if err != nil {
+		return 0, err
+	}
+
+	if st == nil {
+		return 1, nil
+	}
+	return st.Multiplier, nil
+}
+
+// Пересчитывает и сохраняет уровень пользователя. Если уровни не настроены, ничего не делает
+func (t *tierUsecase) Recalculate(ctx context.Context, userid string) (*tier.Status, error) {
+	if len(t.tiers) == 0 {
+		return nil, nil
+	}
+
+	st, err := t.status(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.tierRepo.Save(ctx, userid, st.Tier, st.Progress)
+	if err != nil {
+		return nil, err
+	}
+
+	t.logger.Infow("[TIER RECALCULATED]", "user", userid, "tier", st.Tier, "progress", st.Progress)
+	return st, nil
+}
+
+// Текущий уровень пользователя, только чтение: сохраненный уровень обновляет Recalculate при начислениях
+func (t *tierUsecase) GetStatus(ctx context.Context, userid string) (*tier.Status, error) {
+	if len(t.tiers) == 0 {
+		return nil, tier.ErrDisabled
+	}
+
+	return t.status(ctx, userid)
+}
+
+func (t *tierUsecase) status(ctx context.Context, userid string) (*tier.Status, error) {
+	var progress float64
+	var err error
+
+	switch t.basis {
+	case tier.BasisSpend:
+		progress, err = t.tierRepo.GetSpent(ctx, userid, t.window)
+	default:
+		progress, err = t.tierRepo.GetLifetimeAccrued(ctx, userid)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.tiers.Status(t.basis, progress), nil
+}
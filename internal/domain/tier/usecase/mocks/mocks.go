@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/tier/usecase (interfaces: TierRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/tier/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/tier/usecase TierRepo
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTierRepo is a mock of TierRepo interface.
+type MockTierRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTierRepoMockRecorder
+}
+
+// MockTierRepoMockRecorder is the mock recorder for MockTierRepo.
+type MockTierRepoMockRecorder struct {
+	mock *MockTierRepo
+}
+
+// NewMockTierRepo creates a new mock instance.
+func NewMockTierRepo(ctrl *gomock.Controller) *MockTierRepo {
+	mock := &MockTierRepo{ctrl: ctrl}
+	mock.recorder = &MockTierRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTierRepo) EXPECT() *MockTierRepoMockRecorder {
+	return m.recorder
+}
+
+// GetLifetimeAccrued mocks base method.
+func (m *MockTierRepo) GetLifetimeAccrued(arg0 context.Context, arg1 string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLifetimeAccrued", arg0, arg1)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLifetimeAccrued indicates an expected call of GetLifetimeAccrued.
+func (mr *MockTierRepoMockRecorder) GetLifetimeAccrued(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLifetimeAccrued", reflect.TypeOf((*MockTierRepo)(nil).GetLifetimeAccrued), arg0, arg1)
+}
+
+// GetSpent mocks base method.
+func (m *MockTierRepo) GetSpent(arg0 context.Context, arg1 string, arg2 time.Duration) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpent indicates an expected call of GetSpent.
+func (mr *MockTierRepoMockRecorder) GetSpent(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpent", reflect.TypeOf((*MockTierRepo)(nil).GetSpent), arg0, arg1, arg2)
+}
+
+// Save mocks base method.
+func (m *MockTierRepo) Save(arg0 context.Context, arg1, arg2 string, arg3 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTierRepoMockRecorder) Save(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTierRepo)(nil).Save), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	"time"
+)
+
+type TierRepo interface {
+	Save(ctx context.Context, userid string, name string, progress float64) error
+	GetLifetimeAccrued(ctx context.Context, userid string) (float64, error)
+	GetSpent(ctx context.Context, userid string, window time.Duration) (float64, error)
+}
@@ -0,0 +1,49 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/tier"
+	"github.com/benderr/gophermart/internal/domain/tier/usecase"
+	"github.com/benderr/gophermart/internal/domain/tier/usecase/mocks"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMultiplier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tiers, err := tier.Parse("Bronze:0:1,Silver:1000:1.25,Gold:5000:1.5")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	mockTierRepo := mocks.NewMockTierRepo(ctrl)
+	tierUsecase := usecase.New(mockTierRepo, tiers, tier.BasisSpend, time.Hour, mocklogger.New())
+
+	t.Run("Multiplier follows spend window", func(t *testing.T) {
+		//траты старше окна выпали, уровень понижается до начисления, а не после
+		mockTierRepo.EXPECT().GetSpent(gomock.Any(), "user", time.Hour).Return(1500.0, nil)
+		mockTierRepo.EXPECT().Save(gomock.Any(), "user", "Silver", 1500.0).Return(nil)
+
+		multiplier, err := tierUsecase.Multiplier(context.Background(), "user")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1.25, multiplier)
+		}
+	})
+
+	t.Run("Tiers disabled", func(t *testing.T) {
+		disabled := usecase.New(mockTierRepo, nil, tier.BasisSpend, time.Hour, mocklogger.New())
+
+		multiplier, err := disabled.Multiplier(context.Background(), "user")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1.0, multiplier)
+		}
+	})
+}
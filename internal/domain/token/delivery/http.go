@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type TokenUsecase interface {
+	Refresh(ctx context.Context, refresh string) (*token.Pair, error)
+	Revoke(ctx context.Context, sid string) error
+}
+
+type SessionManager interface {
+	GetSessionID(c echo.Context) (string, error)
+}
+
+type Transport interface {
+	Respond(c echo.Context, pair *token.Pair) error
+	RefreshToken(c echo.Context) string
+	Clear(c echo.Context)
+}
+
+type tokenHandler struct {
+	session   SessionManager
+	transport Transport
+	logger    logger.Logger
+	TokenUsecase
+}
+
+type RefreshModel struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Обновление токенов доступно без токена доступа, выход - только с действующим токеном.
+// Для обновления через cookie передается защита от CSRF
+func NewTokenHandlers(publicGroup *echo.Group, privateGroup *echo.Group, tu TokenUsecase, session SessionManager, transport Transport, logger logger.Logger, refreshMiddleware ...echo.MiddlewareFunc) {
+	h := &tokenHandler{
+		TokenUsecase: tu,
+		session:      session,
+		transport:    transport,
+		logger:       logger,
+	}
+
+	publicGroup.POST("/api/user/refresh", h.RefreshHandler, refreshMiddleware...)
+	privateGroup.POST("/api/user/logout", h.LogoutHandler)
+}
+
+func (t *tokenHandler) RefreshHandler(c echo.Context) error {
+	var m RefreshModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if m.RefreshToken == "" {
+		m.RefreshToken = t.transport.RefreshToken(c)
+	}
+
+	if m.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	pair, err := t.Refresh(c.Request().Context(), m.RefreshToken)
+	if err != nil {
+		if errors.Is(err, token.ErrInvalid) || errors.Is(err, token.ErrReused) {
+			t.transport.Clear(c)
+			return c.JSON(http.StatusUnauthorized, httputils.Error(err.Error()))
+		}
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return t.transport.Respond(c, pair)
+}
+
+func (t *tokenHandler) LogoutHandler(c echo.Context) error {
+	sid, err := t.session.GetSessionID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if err := t.Revoke(c.Request().Context(), sid); err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	t.transport.Clear(c)
+	t.logger.Infoln("[LOGOUT]", sid)
+
+	return c.JSON(http.StatusOK, httputils.Ok())
+}
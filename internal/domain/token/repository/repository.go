@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type tokenRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *tokenRepository {
+	return &tokenRepository{db: db, log: log}
+}
+
+func (t *tokenRepository) CreateSession(ctx context.Context, userid string) (string, error) {
+	row := transactor.FromContext(ctx, t.db).QueryRowContext(ctx, `INSERT INTO sessions (user_id) VALUES($1) RETURNING id`, userid)
+	var sid string
+	err := row.Scan(&sid)
+	return sid, err
+}
+
+func (t *tokenRepository) CreateRefresh(ctx context.Context, sid string, hash string, ttl time.Duration) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `INSERT INTO refresh_tokens (session_id, token_hash, expires_at)
+	VALUES($1, $2, NOW() + $3 * interval '1 second')`, sid, hash, ttl.Seconds())
+	return err
+}
+
+// Токен обновления по хешу с блокировкой строки
+func (t *tokenRepository) GetRefresh(ctx context.Context, hash string) (*token.RefreshToken, error) {
+	row := transactor.FromContext(ctx, t.db).QueryRowContext(ctx, `SELECT r.id, r.session_id, s.user_id, r.used_at IS NOT NULL, r.expires_at <= NOW(), s.revoked_at IS NOT NULL
+	FROM refresh_tokens r
+	JOIN sessions s ON s.id = r.session_id
+	WHERE r.token_hash=$1
+	FOR UPDATE OF r`, hash)
+
+	var rt token.RefreshToken
+	err := row.Scan(&rt.ID, &rt.SessionID, &rt.UserID, &rt.Used, &rt.Expired, &rt.Revoked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, token.ErrInvalid
+		}
+		return nil, err
+	}
+	return &rt, nil
+}
+
+func (t *tokenRepository) MarkUsed(ctx context.Context, id string) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `UPDATE refresh_tokens SET used_at=NOW() WHERE id=$1`, id)
+	return err
+}
+
+func (t *tokenRepository) RevokeSession(ctx context.Context, sid string) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `UPDATE sessions SET revoked_at=NOW() WHERE id=$1 AND revoked_at IS NULL`, sid)
+	t.log.Infoln("[REVOKE SESSION]", sid)
+	return err
+}
+
+// Отзывает все сессии пользователя
+func (t *tokenRepository) RevokeUser(ctx context.Context, userid string) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `UPDATE sessions SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL`, userid)
+	t.log.Infoln("[REVOKE USER SESSIONS]", userid)
+	return err
+}
+
+func (t *tokenRepository) GetSession(ctx context.Context, sid string) (*token.Session, error) {
+	row := transactor.FromContext(ctx, t.db).QueryRowContext(ctx, `SELECT s.id, s.user_id, u.role, s.revoked_at IS NOT NULL
+	FROM sessions s JOIN users u ON u.id = s.user_id
+	WHERE s.id=$1`, sid)
+	var s token.Session
+	err := row.Scan(&s.ID, &s.UserID, &s.Role, &s.Revoked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, token.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,61 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+// Токен доступа и токен обновления одной сессии
+type Pair struct {
+	AccessToken  string `json:"-"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	//срок жизни токена доступа в секундах
+	ExpiresIn int64  `json:"expires_in"`
+	SessionID string `json:"-"`
+}
+
+// Сохраненный токен обновления. Токены одной сессии образуют семейство:
+// каждое обновление выдает новый токен, а старый помечается использованным
+type RefreshToken struct {
+	ID        string
+	SessionID string
+	UserID    string
+	Used      bool
+	Expired   bool
+	Revoked   bool
+}
+
+// Серверная сессия, к которой привязаны токены доступа
+type Session struct {
+	ID     string
+	UserID string
+	//текущая роль владельца, а не роль на момент выдачи токена
+	Role    string
+	Revoked bool
+}
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrInvalid         = errors.New("invalid refresh token")
+	//повторное использование токена обновления, сессия отозвана
+	ErrReused = errors.New("refresh token reused")
+)
+
+// Случайный токен обновления и его хеш для хранения
+func Generate() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+	return raw, Hash(raw), nil
+}
+
+func Hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type tokenUsecase struct {
+	tokenRepo  TokenRepo
+	userRepo   UserRepo
+	signer     Signer
+	transactor Transactor
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	logger     logger.Logger
+}
+
+func New(tr TokenRepo, ur UserRepo, s Signer, t Transactor, accessTTL, refreshTTL time.Duration, l logger.Logger) *tokenUsecase {
+	return &tokenUsecase{
+		tokenRepo:  tr,
+		userRepo:   ur,
+		signer:     s,
+		transactor: t,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		logger:     l,
+	}
+}
+
+// Открывает новую сессию пользователя и выдает пару токенов
+func (t *tokenUsecase) Issue(ctx context.Context, userid string) (*token.Pair, error) {
+	var pair *token.Pair
+	err := t.transactor.Within(ctx, func(ctx context.Context) error {
+		sid, err := t.tokenRepo.CreateSession(ctx, userid)
+		if err != nil {
+			return err
+		}
+
+		pair, err = t.issue(ctx, userid, sid)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// Обменивает токен обновления на новую пару. Использованный токен больше не принимается,
+// повторное предъявление считается кражей и отзывает всю сессию
+func (t *tokenUsecase) Refresh(ctx context.Context, refresh string) (*token.Pair, error) {
+	var pair *token.Pair
+	reused := false
+
+	err := t.transactor.Within(ctx, func(ctx context.Context) error {
+		rt, err := t.tokenRepo.GetRefresh(ctx, token.Hash(refresh))
+		if err != nil {
+			return err
+		}
+
+		if rt.Revoked || rt.Expired {
+			return token.ErrInvalid
+		}
+
+		if rt.Used {
+			//отзыв сессии должен сохраниться, поэтому транзакцию не откатываем
+			reused = true
+			t.logger.Infoln("[REFRESH TOKEN REUSED]", rt.UserID, rt.SessionID)
+			return t.tokenRepo.RevokeSession(ctx, rt.SessionID)
+		}
+
+		if err := t.tokenRepo.MarkUsed(ctx, rt.ID); err != nil {
+			return err
+		}
+
+		pair, err = t.issue(ctx, rt.UserID, rt.SessionID)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reused {
+		return nil, token.ErrReused
+	}
+
+	return pair, nil
+}
+
+func (t *tokenUsecase) Revoke(ctx context.Context, sid string) error {
+	return t.tokenRepo.RevokeSession(ctx, sid)
+}
+
+// Отзывает все сессии пользователя
+func (t *tokenUsecase) RevokeAll(ctx context.Context, userid string) error {
+	return t.tokenRepo.RevokeUser(ctx, userid)
+}
+
+func (t *tokenUsecase) GetSession(ctx context.Context, sid string) (*token.Session, error) {
+	return t.tokenRepo.GetSession(ctx, sid)
+}
+
+// Роль читается при каждой выдаче, поэтому обновление токена подхватывает ее изменение
+func (t *tokenUsecase) issue(ctx context.Context, userid string, sid string) (*token.Pair, error) {
+	usr, err := t.userRepo.GetUserByID(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, hash, err := token.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.tokenRepo.CreateRefresh(ctx, sid, hash, t.refreshTTL); err != nil {
+		return nil, err
+	}
+
+	access, err := t.signer.Create(userid, sid, string(usr.Role))
+	if err != nil {
+		return nil, err
+	}
+
+	return &token.Pair{
+		AccessToken:  access,
+		RefreshToken: raw,
+		ExpiresIn:    int64(t.accessTTL.Seconds()),
+		SessionID:    sid,
+	}, nil
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type TokenRepo interface {
+	CreateSession(ctx context.Context, userid string) (string, error)
+	CreateRefresh(ctx context.Context, sid string, hash string, ttl time.Duration) error
+	GetRefresh(ctx context.Context, hash string) (*token.RefreshToken, error)
+	MarkUsed(ctx context.Context, id string) error
+	RevokeSession(ctx context.Context, sid string) error
+	RevokeUser(ctx context.Context, userid string) error
+	GetSession(ctx context.Context, sid string) (*token.Session, error)
+}
+
+type UserRepo interface {
+	GetUserByID(ctx context.Context, id string) (*user.User, error)
+}
+
+// Подписывает токены доступа
+type Signer interface {
+	Create(userID string, sid string, role string) (string, error)
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
@@ -0,0 +1,71 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/domain/token/usecase"
+	"github.com/benderr/gophermart/internal/domain/token/usecase/mocks"
+	"github.com/benderr/gophermart/internal/domain/user"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenRepo := mocks.NewMockTokenRepo(ctrl)
+	mockUserRepo := mocks.NewMockUserRepo(ctrl)
+	mockSigner := mocks.NewMockSigner(ctrl)
+	mockTransactor := mocktransactor.New()
+	mockLogger := mocklogger.New()
+	tokenUsecase := usecase.New(mockTokenRepo, mockUserRepo, mockSigner, mockTransactor, 15*time.Minute, time.Hour, mockLogger)
+
+	t.Run("Refresh rotates token", func(t *testing.T) {
+		rt := &token.RefreshToken{ID: "rt", SessionID: "sid", UserID: "testuserid"}
+
+		mockTokenRepo.EXPECT().GetRefresh(gomock.Any(), token.Hash("refresh")).Return(rt, nil)
+		mockTokenRepo.EXPECT().MarkUsed(gomock.Any(), rt.ID).Return(nil)
+		mockTokenRepo.EXPECT().CreateRefresh(gomock.Any(), rt.SessionID, gomock.Any(), time.Hour).Return(nil)
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), rt.UserID).Return(&user.User{ID: rt.UserID, Role: user.RoleSupport}, nil)
+		mockSigner.EXPECT().Create(rt.UserID, rt.SessionID, "support").Return("access", nil)
+
+		pair, err := tokenUsecase.Refresh(context.Background(), "refresh")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "access", pair.AccessToken)
+			assert.NotEqual(t, "refresh", pair.RefreshToken)
+			assert.Equal(t, int64(900), pair.ExpiresIn)
+		}
+	})
+
+	t.Run("Reused token revokes session", func(t *testing.T) {
+		rt := &token.RefreshToken{ID: "rt", SessionID: "sid", UserID: "testuserid", Used: true}
+
+		mockTokenRepo.EXPECT().GetRefresh(gomock.Any(), token.Hash("refresh")).Return(rt, nil)
+		mockTokenRepo.EXPECT().RevokeSession(gomock.Any(), rt.SessionID).Return(nil)
+
+		_, err := tokenUsecase.Refresh(context.Background(), "refresh")
+
+		if assert.Error(t, err) {
+			assert.Equal(t, token.ErrReused, err)
+		}
+	})
+
+	t.Run("Revoked session", func(t *testing.T) {
+		rt := &token.RefreshToken{ID: "rt", SessionID: "sid", UserID: "testuserid", Revoked: true}
+
+		mockTokenRepo.EXPECT().GetRefresh(gomock.Any(), token.Hash("refresh")).Return(rt, nil)
+
+		_, err := tokenUsecase.Refresh(context.Background(), "refresh")
+
+		if assert.Error(t, err) {
+			assert.Equal(t, token.ErrInvalid, err)
+		}
+	})
+}
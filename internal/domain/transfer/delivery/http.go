@@ -0,0 +1,88 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/transfer"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type TransferUsecase interface {
+	Transfer(ctx context.Context, fromUserID string, toLogin string, sum float64) (*transfer.Transfer, error)
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type transferHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	TransferUsecase
+}
+
+type TransferModel struct {
+	Login string   `json:"login" validate:"required"`
+	Sum   *float64 `json:"sum" validate:"required"`
+}
+
+// transferMiddleware применяется к переводу баллов, как и к списаниям
+func NewTransferHandlers(group *echo.Group, tu TransferUsecase, session SessionManager, logger logger.Logger, transferMiddleware ...echo.MiddlewareFunc) {
+	h := &transferHandler{
+		TransferUsecase: tu,
+		session:         session,
+		logger:          logger,
+	}
+
+	g := group.Group("/api/user")
+
+	g.POST("/balance/transfer", h.TransferHandler, transferMiddleware...)
+}
+
+func (t *transferHandler) TransferHandler(c echo.Context) error {
+	var m TransferModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := t.session.GetUserID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	created, err := t.Transfer(c.Request().Context(), userid, m.Login, *m.Sum)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, transfer.ErrInsufficientFunds):
+			return c.JSON(http.StatusPaymentRequired, httputils.Error("insufficient funds"))
+		case errors.Is(err, transfer.ErrRecipientNotFound):
+			return c.JSON(http.StatusNotFound, httputils.Error("recipient not found"))
+		case errors.Is(err, transfer.ErrSelfTransfer),
+			errors.Is(err, transfer.ErrInvalidAmount),
+			errors.Is(err, transfer.ErrBelowMinimum),
+			errors.Is(err, transfer.ErrAboveMaximum):
+			return c.JSON(http.StatusUnprocessableEntity, httputils.Error(err.Error()))
+		case errors.Is(err, transfer.ErrDailyLimitExceeded),
+			errors.Is(err, transfer.ErrBalanceBlocked):
+			return c.JSON(http.StatusForbidden, httputils.Error(err.Error()))
+		}
+
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	t.logger.Infow("[TRANSFER SUCCESS]", "transfer", created)
+
+	return c.JSON(http.StatusOK, created)
+}
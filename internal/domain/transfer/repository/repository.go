@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/benderr/gophermart/internal/domain/transfer"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type transferRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *transferRepository {
+	return &transferRepository{db: db, log: log}
+}
+
+func (t *transferRepository) Create(ctx context.Context, fromUserID string, toUserID string, sum float64) (*transfer.Transfer, error) {
+	row := transactor.FromContext(ctx, t.db).QueryRowContext(ctx, `INSERT INTO transfers (from_user_id, to_user_id, amount) VALUES($1, $2, $3)
+	RETURNING id, from_user_id, to_user_id, amount, created_at`, fromUserID, toUserID, sum)
+
+	var tr transfer.Transfer
+	err := row.Scan(&tr.ID, &tr.FromUserID, &tr.ToUserID, &tr.Sum, &tr.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	t.log.Infoln("[CREATE TRANSFER]", fromUserID, toUserID, sum)
+	return &tr, nil
+}
+
+func (t *transferRepository) GetSentToday(ctx context.Context, userid string) (float64, error) {
+	row := transactor.FromContext(ctx, t.db).QueryRowContext(ctx, `SELECT COALESCE(SUM(amount), 0) FROM transfers WHERE from_user_id=$1 AND created_at >= date_trunc('day', NOW())`, userid)
+	var sum float64
+	err := row.Scan(&sum)
+	return sum, err
+}
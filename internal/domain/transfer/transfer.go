@@ -0,0 +1,59 @@
+package transfer
+
+import (
+	"errors"
+	"time"
+)
+
+type Transfer struct {
+	ID         string    `json:"id"`
+	To         string    `json:"to"`
+	Sum        float64   `json:"sum"`
+	CreatedAt  time.Time `json:"created_at"`
+	FromUserID string    `json:"-"`
+	ToUserID   string    `json:"-"`
+}
+
+// Ограничения переводов, нулевое значение поля отключает правило
+type Limits struct {
+	Min        float64
+	Max        float64
+	DailyLimit float64
+}
+
+func (l Limits) CheckAmount(sum float64) error {
+	if sum <= 0 {
+		return ErrInvalidAmount
+	}
+
+	if l.Min > 0 && sum < l.Min {
+		return ErrBelowMinimum
+	}
+
+	if l.Max > 0 && sum > l.Max {
+		return ErrAboveMaximum
+	}
+
+	return nil
+}
+
+// sentToday - сумма переводов пользователя за текущие сутки
+func (l Limits) CheckDaily(sum float64, sentToday float64) error {
+	if l.DailyLimit > 0 && sentToday+sum > l.DailyLimit {
+		return ErrDailyLimitExceeded
+	}
+	return nil
+}
+
+var (
+	ErrInvalidAmount      = errors.New("invalid transfer amount")
+	ErrBelowMinimum       = errors.New("transfer amount below minimum")
+	ErrAboveMaximum       = errors.New("transfer amount above maximum")
+	ErrDailyLimitExceeded = errors.New("daily transfer limit exceeded")
+	//перевод самому себе
+	ErrSelfTransfer      = errors.New("transfer to yourself")
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	//переводы запрещены до погашения долга после отзыва начислений
+	ErrBalanceBlocked = errors.New("transfers blocked until debt is repaid")
+)
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/benderr/gophermart/internal/domain/transfer"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type transferUsecase struct {
+	transferRepo TransferRepo
+	balanceRepo  BalanceRepo
+	userRepo     UserRepo
+	points       Points
+	transactor   Transactor
+	limits       transfer.Limits
+	logger       logger.Logger
+}
+
+func New(tr TransferRepo, br BalanceRepo, ur UserRepo, pts Points, t Transactor, limits transfer.Limits, l logger.Logger) *transferUsecase {
+	return &transferUsecase{
+		transferRepo: tr,
+		balanceRepo:  br,
+		userRepo:     ur,
+		points:       pts,
+		transactor:   t,
+		limits:       limits,
+		logger:       l}
+}
+
+func (t *transferUsecase) Transfer(ctx context.Context, fromUserID string, toLogin string, sum float64) (*transfer.Transfer, error) {
+	if err := t.limits.CheckAmount(sum); err != nil {
+		return nil, err
+	}
+
+	recipient, err := t.userRepo.GetUserByLogin(ctx, toLogin)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil, transfer.ErrRecipientNotFound
+		}
+		return nil, err
+	}
+
+	if recipient.ID == fromUserID {
+		return nil, transfer.ErrSelfTransfer
+	}
+
+	var created *transfer.Transfer
+	err = t.transactor.Within(ctx, func(ctx context.Context) error {
+		//блокируем оба баланса всегда в одном порядке, чтобы встречные переводы не приводили к взаимной блокировке
+		ids := []string{fromUserID, recipient.ID}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if err := t.balanceRepo.Lock(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		bal, err := t.balanceRepo.GetBalanceByUser(ctx, fromUserID)
+		if err != nil {
+			return err
+		}
+
+		if bal.Blocked() {
+			return transfer.ErrBalanceBlocked
+		}
+
+		if bal.Spendable() < sum {
+			return transfer.ErrInsufficientFunds
+		}
+
+		if t.limits.DailyLimit > 0 {
+			sent, err := t.transferRepo.GetSentToday(ctx, fromUserID)
+			if err != nil {
+				return err
+			}
+
+			if err := t.limits.CheckDaily(sum, sent); err != nil {
+				return err
+			}
+		}
+
+		created, err = t.transferRepo.Create(ctx, fromUserID, recipient.ID, sum)
+		if err != nil {
+			return err
+		}
+
+		err = t.balanceRepo.Debit(ctx, fromUserID, sum)
+		if err != nil {
+			return err
+		}
+
+		net, err := t.balanceRepo.Add(ctx, recipient.ID, &sum)
+		if err != nil {
+			return err
+		}
+
+		return t.points.Transfer(ctx, fromUserID, recipient.ID, sum, net)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	created.To = recipient.Login
+	return created, nil
+}
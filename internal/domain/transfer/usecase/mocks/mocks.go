@@ -0,0 +1,228 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/transfer/usecase (interfaces: TransferRepo,BalanceRepo,UserRepo,Points)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/transfer/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/transfer/usecase TransferRepo,BalanceRepo,UserRepo,Points
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	balance "github.com/benderr/gophermart/internal/domain/balance"
+	transfer "github.com/benderr/gophermart/internal/domain/transfer"
+	user "github.com/benderr/gophermart/internal/domain/user"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransferRepo is a mock of TransferRepo interface.
+type MockTransferRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferRepoMockRecorder
+}
+
+// MockTransferRepoMockRecorder is the mock recorder for MockTransferRepo.
+type MockTransferRepoMockRecorder struct {
+	mock *MockTransferRepo
+}
+
+// NewMockTransferRepo creates a new mock instance.
+func NewMockTransferRepo(ctrl *gomock.Controller) *MockTransferRepo {
+	mock := &MockTransferRepo{ctrl: ctrl}
+	mock.recorder = &MockTransferRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferRepo) EXPECT() *MockTransferRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransferRepo) Create(arg0 context.Context, arg1, arg2 string, arg3 float64) (*transfer.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*transfer.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransferRepoMockRecorder) Create(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransferRepo)(nil).Create), arg0, arg1, arg2, arg3)
+}
+
+// GetSentToday mocks base method.
+func (m *MockTransferRepo) GetSentToday(arg0 context.Context, arg1 string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSentToday", arg0, arg1)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSentToday indicates an expected call of GetSentToday.
+func (mr *MockTransferRepoMockRecorder) GetSentToday(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSentToday", reflect.TypeOf((*MockTransferRepo)(nil).GetSentToday), arg0, arg1)
+}
+
+// MockBalanceRepo is a mock of BalanceRepo interface.
+type MockBalanceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceRepoMockRecorder
+}
+
+// MockBalanceRepoMockRecorder is the mock recorder for MockBalanceRepo.
+type MockBalanceRepoMockRecorder struct {
+	mock *MockBalanceRepo
+}
+
+// NewMockBalanceRepo creates a new mock instance.
+func NewMockBalanceRepo(ctrl *gomock.Controller) *MockBalanceRepo {
+	mock := &MockBalanceRepo{ctrl: ctrl}
+	mock.recorder = &MockBalanceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceRepo) EXPECT() *MockBalanceRepoMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockBalanceRepo) Add(arg0 context.Context, arg1 string, arg2 *float64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockBalanceRepoMockRecorder) Add(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBalanceRepo)(nil).Add), arg0, arg1, arg2)
+}
+
+// Debit mocks base method.
+func (m *MockBalanceRepo) Debit(arg0 context.Context, arg1 string, arg2 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Debit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Debit indicates an expected call of Debit.
+func (mr *MockBalanceRepoMockRecorder) Debit(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debit", reflect.TypeOf((*MockBalanceRepo)(nil).Debit), arg0, arg1, arg2)
+}
+
+// GetBalanceByUser mocks base method.
+func (m *MockBalanceRepo) GetBalanceByUser(arg0 context.Context, arg1 string) (*balance.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceByUser", arg0, arg1)
+	ret0, _ := ret[0].(*balance.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceByUser indicates an expected call of GetBalanceByUser.
+func (mr *MockBalanceRepoMockRecorder) GetBalanceByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceByUser", reflect.TypeOf((*MockBalanceRepo)(nil).GetBalanceByUser), arg0, arg1)
+}
+
+// Lock mocks base method.
+func (m *MockBalanceRepo) Lock(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockBalanceRepoMockRecorder) Lock(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockBalanceRepo)(nil).Lock), arg0, arg1)
+}
+
+// MockUserRepo is a mock of UserRepo interface.
+type MockUserRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepoMockRecorder
+}
+
+// MockUserRepoMockRecorder is the mock recorder for MockUserRepo.
+type MockUserRepoMockRecorder struct {
+	mock *MockUserRepo
+}
+
+// NewMockUserRepo creates a new mock instance.
+func NewMockUserRepo(ctrl *gomock.Controller) *MockUserRepo {
+	mock := &MockUserRepo{ctrl: ctrl}
+	mock.recorder = &MockUserRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
+	return m.recorder
+}
+
+// GetUserByLogin mocks base method.
+func (m *MockUserRepo) GetUserByLogin(arg0 context.Context, arg1 string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByLogin", arg0, arg1)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByLogin indicates an expected call of GetUserByLogin.
+func (mr *MockUserRepoMockRecorder) GetUserByLogin(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByLogin", reflect.TypeOf((*MockUserRepo)(nil).GetUserByLogin), arg0, arg1)
+}
+
+// MockPoints is a mock of Points interface.
+type MockPoints struct {
+	ctrl     *gomock.Controller
+	recorder *MockPointsMockRecorder
+}
+
+// MockPointsMockRecorder is the mock recorder for MockPoints.
+type MockPointsMockRecorder struct {
+	mock *MockPoints
+}
+
+// NewMockPoints creates a new mock instance.
+func NewMockPoints(ctrl *gomock.Controller) *MockPoints {
+	mock := &MockPoints{ctrl: ctrl}
+	mock.recorder = &MockPointsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPoints) EXPECT() *MockPointsMockRecorder {
+	return m.recorder
+}
+
+// Transfer mocks base method.
+func (m *MockPoints) Transfer(arg0 context.Context, arg1, arg2 string, arg3, arg4 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockPointsMockRecorder) Transfer(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockPoints)(nil).Transfer), arg0, arg1, arg2, arg3, arg4)
+}
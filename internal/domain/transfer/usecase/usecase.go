@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/transfer"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type TransferRepo interface {
+	Create(ctx context.Context, fromUserID string, toUserID string, sum float64) (*transfer.Transfer, error)
+	GetSentToday(ctx context.Context, userid string) (float64, error)
+}
+
+type BalanceRepo interface {
+	Lock(ctx context.Context, userid string) error
+	GetBalanceByUser(ctx context.Context, userid string) (*balance.Balance, error)
+	Debit(ctx context.Context, userid string, amount float64) error
+	//возвращает сумму, зачисленную на баланс после погашения долга
+	Add(ctx context.Context, userid string, balance *float64) (float64, error)
+}
+
+type UserRepo interface {
+	GetUserByLogin(ctx context.Context, login string) (*user.User, error)
+}
+
+type Points interface {
+	Transfer(ctx context.Context, from string, to string, amount float64, net float64) error
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
@@ -0,0 +1,79 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/transfer"
+	"github.com/benderr/gophermart/internal/domain/transfer/usecase"
+	"github.com/benderr/gophermart/internal/domain/transfer/usecase/mocks"
+	"github.com/benderr/gophermart/internal/domain/user"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransferRepo := mocks.NewMockTransferRepo(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	mockUserRepo := mocks.NewMockUserRepo(ctrl)
+	mockPoints := mocks.NewMockPoints(ctrl)
+	mockTransactor := mocktransactor.New()
+	mockLogger := mocklogger.New()
+	transferUsecase := usecase.New(mockTransferRepo, mockBalanceRepo, mockUserRepo, mockPoints, mockTransactor, transfer.Limits{}, mockLogger)
+
+	t.Run("Transfer success locks balances in order", func(t *testing.T) {
+		from := "b-sender"
+		to := "a-recipient"
+		var sum float64 = 40
+
+		mockUserRepo.EXPECT().GetUserByLogin(gomock.Any(), "friend").Return(&user.User{ID: to, Login: "friend"}, nil)
+
+		gomock.InOrder(
+			mockBalanceRepo.EXPECT().Lock(gomock.Any(), to).Return(nil),
+			mockBalanceRepo.EXPECT().Lock(gomock.Any(), from).Return(nil),
+		)
+
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), from).Return(&balance.Balance{Current: 100}, nil)
+		mockTransferRepo.EXPECT().Create(gomock.Any(), from, to, sum).Return(&transfer.Transfer{ID: "id", Sum: sum}, nil)
+		mockBalanceRepo.EXPECT().Debit(gomock.Any(), from, sum).Return(nil)
+		mockBalanceRepo.EXPECT().Add(gomock.Any(), to, gomock.Any()).Return(sum, nil)
+		mockPoints.EXPECT().Transfer(gomock.Any(), from, to, sum, sum).Return(nil)
+
+		created, err := transferUsecase.Transfer(context.Background(), from, "friend", sum)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "friend", created.To)
+		}
+	})
+
+	t.Run("Transfer to yourself", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByLogin(gomock.Any(), "me").Return(&user.User{ID: "userid", Login: "me"}, nil)
+
+		_, err := transferUsecase.Transfer(context.Background(), "userid", "me", 10)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, transfer.ErrSelfTransfer, err)
+		}
+	})
+
+	t.Run("Transfer insufficient funds", func(t *testing.T) {
+		from := "sender"
+		to := "recipient"
+
+		mockUserRepo.EXPECT().GetUserByLogin(gomock.Any(), "friend").Return(&user.User{ID: to, Login: "friend"}, nil)
+		mockBalanceRepo.EXPECT().Lock(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mockBalanceRepo.EXPECT().GetBalanceByUser(gomock.Any(), from).Return(&balance.Balance{Current: 100, Held: 80}, nil)
+
+		_, err := transferUsecase.Transfer(context.Background(), from, "friend", 30)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, transfer.ErrInsufficientFunds, err)
+		}
+	})
+}
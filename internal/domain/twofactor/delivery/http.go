@@ -0,0 +1,240 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/twofactor"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// Заголовок с кодом 2FA для операций списания
+const CodeHeader = "X-OTP-Code"
+
+type TwoFactorUsecase interface {
+	GetSettings(ctx context.Context, userid string) (*twofactor.Settings, error)
+	Enroll(ctx context.Context, userid string) (*twofactor.Enrollment, error)
+	Confirm(ctx context.Context, userid string, code string) ([]string, error)
+	Disable(ctx context.Context, userid string, code string) error
+	SetRequireForWithdrawals(ctx context.Context, userid string, required bool, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userid string, code string) ([]string, error)
+	CheckWithdrawal(ctx context.Context, userid string, code string) error
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type twoFactorHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	TwoFactorUsecase
+}
+
+type CodeModel struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type WithdrawalsModel struct {
+	CodeModel
+	Required *bool `json:"required" validate:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func NewTwoFactorHandlers(group *echo.Group, tu TwoFactorUsecase, session SessionManager, logger logger.Logger) {
+	h := &twoFactorHandler{
+		TwoFactorUsecase: tu,
+		session:          session,
+		logger:           logger,
+	}
+
+	g := group.Group("/api/user/2fa")
+
+	g.GET("", h.GetSettingsHandler)
+	g.POST("/enroll", h.EnrollHandler)
+	g.POST("/confirm", h.ConfirmHandler)
+	g.POST("/disable", h.DisableHandler)
+	g.POST("/withdrawals", h.WithdrawalsHandler)
+	g.POST("/recovery-codes", h.RecoveryCodesHandler)
+}
+
+// Требует код 2FA в заголовке X-OTP-Code, если пользователь включил его для списаний
+func RequireForWithdrawals(tu TwoFactorUsecase, session SessionManager, logger logger.Logger) echo.MiddlewareFunc {
+	h := &twoFactorHandler{
+		TwoFactorUsecase: tu,
+		session:          session,
+		logger:           logger,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userid, err := h.session.GetUserID(c)
+			if err != nil {
+				h.logger.Errorln(err)
+				return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+			}
+
+			if err := h.CheckWithdrawal(c.Request().Context(), userid, c.Request().Header.Get(CodeHeader)); err != nil {
+				return h.error(c, err)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func (t *twoFactorHandler) GetSettingsHandler(c echo.Context) error {
+	userid, err := t.session.GetUserID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	settings, err := t.GetSettings(c.Request().Context(), userid)
+	if err != nil {
+		return t.error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+func (t *twoFactorHandler) EnrollHandler(c echo.Context) error {
+	userid, err := t.session.GetUserID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	enrollment, err := t.Enroll(c.Request().Context(), userid)
+	if err != nil {
+		return t.error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, enrollment)
+}
+
+func (t *twoFactorHandler) ConfirmHandler(c echo.Context) error {
+	var m CodeModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := t.session.GetUserID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	codes, err := t.Confirm(c.Request().Context(), userid, m.Code)
+	if err != nil {
+		return t.error(c, err)
+	}
+
+	t.logger.Infoln("[2FA ENABLED]", userid)
+
+	return c.JSON(http.StatusOK, &RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (t *twoFactorHandler) DisableHandler(c echo.Context) error {
+	var m CodeModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := t.session.GetUserID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if err := t.Disable(c.Request().Context(), userid, m.Code); err != nil {
+		return t.error(c, err)
+	}
+
+	t.logger.Infoln("[2FA DISABLED]", userid)
+
+	return c.JSON(http.StatusOK, httputils.Ok())
+}
+
+func (t *twoFactorHandler) WithdrawalsHandler(c echo.Context) error {
+	var m WithdrawalsModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := t.session.GetUserID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if err := t.SetRequireForWithdrawals(c.Request().Context(), userid, *m.Required, m.Code); err != nil {
+		return t.error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, httputils.Ok())
+}
+
+func (t *twoFactorHandler) RecoveryCodesHandler(c echo.Context) error {
+	var m CodeModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := t.session.GetUserID(c)
+	if err != nil {
+		t.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	codes, err := t.RegenerateRecoveryCodes(c.Request().Context(), userid, m.Code)
+	if err != nil {
+		return t.error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Ошибки 2FA отдаем клиенту как есть, с разными кодами ответа
+func (t *twoFactorHandler) error(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, twofactor.ErrRequired),
+		errors.Is(err, twofactor.ErrInvalidCode):
+		return c.JSON(http.StatusForbidden, httputils.Error(err.Error()))
+	case errors.Is(err, twofactor.ErrLocked):
+		return c.JSON(http.StatusTooManyRequests, httputils.Error(err.Error()))
+	case errors.Is(err, twofactor.ErrNotEnrolled),
+		errors.Is(err, twofactor.ErrNotEnabled),
+		errors.Is(err, twofactor.ErrAlreadyEnabled):
+		return c.JSON(http.StatusConflict, httputils.Error(err.Error()))
+	}
+
+	t.logger.Errorln(err)
+	return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/twofactor"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+const settingsQuery = `SELECT secret, enabled, require_for_withdrawals, last_counter, COALESCE(locked_until > NOW(), false),
+	(SELECT COUNT(*) FROM recovery_codes r WHERE r.user_id = t.user_id AND r.used_at IS NULL)
+FROM two_factor t
+WHERE user_id=$1`
+
+type twoFactorRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *twoFactorRepository {
+	return &twoFactorRepository{db: db, log: log}
+}
+
+func (t *twoFactorRepository) Get(ctx context.Context, userid string) (*twofactor.Settings, error) {
+	return scanSettings(transactor.FromContext(ctx, t.db).QueryRowContext(ctx, settingsQuery, userid))
+}
+
+// Настройки с блокировкой строки
+func (t *twoFactorRepository) GetForUpdate(ctx context.Context, userid string) (*twofactor.Settings, error) {
+	return scanSettings(transactor.FromContext(ctx, t.db).QueryRowContext(ctx, settingsQuery+` FOR UPDATE OF t`, userid))
+}
+
+// Сохраняет новый секрет, пока 2FA не подтверждена. Подключенный секрет не перезаписывается
+func (t *twoFactorRepository) SavePending(ctx context.Context, userid string, secret string) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `INSERT INTO two_factor (user_id, secret) VALUES($1, $2)
+	ON CONFLICT (user_id) DO UPDATE SET secret=EXCLUDED.secret, last_counter=0, failures=0, locked_until=NULL
+	WHERE two_factor.enabled = false`, userid, secret)
+	return err
+}
+
+func (t *twoFactorRepository) Enable(ctx context.Context, userid string) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `UPDATE two_factor SET enabled=true, enabled_at=NOW() WHERE user_id=$1`, userid)
+	t.log.Infoln("[ENABLE 2FA]", userid)
+	return err
+}
+
+func (t *twoFactorRepository) Delete(ctx context.Context, userid string) error {
+	db := transactor.FromContext(ctx, t.db)
+	if _, err := db.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id=$1`, userid); err != nil {
+		return err
+	}
+
+	_, err := db.ExecContext(ctx, `DELETE FROM two_factor WHERE user_id=$1`, userid)
+	t.log.Infoln("[DISABLE 2FA]", userid)
+	return err
+}
+
+// Запоминает шаг принятого кода и сбрасывает счетчик неудач
+func (t *twoFactorRepository) Accept(ctx context.Context, userid string, counter int64) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `UPDATE two_factor SET last_counter=$2, failures=0, locked_until=NULL WHERE user_id=$1`, userid, counter)
+	return err
+}
+
+// Засчитывает неверный код и блокирует проверку после maxFailures неудач
+func (t *twoFactorRepository) Fail(ctx context.Context, userid string, maxFailures int, lock time.Duration) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `UPDATE two_factor SET failures = failures + 1,
+		locked_until = CASE WHEN failures + 1 >= $2 THEN NOW() + $3 * interval '1 second' ELSE locked_until END
+	WHERE user_id=$1`, userid, maxFailures, lock.Seconds())
+	return err
+}
+
+func (t *twoFactorRepository) SetRequireForWithdrawals(ctx context.Context, userid string, required bool) error {
+	_, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `UPDATE two_factor SET require_for_withdrawals=$2 WHERE user_id=$1`, userid, required)
+	return err
+}
+
+// Заменяет резервные коды пользователя новыми
+func (t *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userid string, hashes []string) error {
+	db := transactor.FromContext(ctx, t.db)
+	if _, err := db.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id=$1`, userid); err != nil {
+		return err
+	}
+
+	_, err := db.ExecContext(ctx, `INSERT INTO recovery_codes (user_id, code_hash) SELECT $1, unnest($2::text[])`, userid, hashes)
+	return err
+}
+
+// Гасит резервный код, false - код не найден или уже использован
+func (t *twoFactorRepository) UseRecoveryCode(ctx context.Context, userid string, hash string) (bool, error) {
+	res, err := transactor.FromContext(ctx, t.db).ExecContext(ctx, `UPDATE recovery_codes SET used_at=NOW()
+	WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL`, userid, hash)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func scanSettings(row *sql.Row) (*twofactor.Settings, error) {
+	var s twofactor.Settings
+	err := row.Scan(&s.Secret, &s.Enabled, &s.RequireForWithdrawals, &s.LastCounter, &s.Locked, &s.RecoveryCodesLeft)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, twofactor.ErrNotEnrolled
+		}
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,60 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	//количество резервных кодов, выдаваемых при подключении
+	RecoveryCodes = 10
+	//допуск на расхождение часов в шагах TOTP
+	Skew = 1
+	//после MaxFailures неверных кодов подряд проверка блокируется на LockDuration
+	MaxFailures  = 5
+	LockDuration = 15 * time.Minute
+)
+
+// Настройки двухфакторной аутентификации пользователя
+type Settings struct {
+	Enabled               bool   `json:"enabled"`
+	RequireForWithdrawals bool   `json:"require_for_withdrawals"`
+	RecoveryCodesLeft     int    `json:"recovery_codes_left"`
+	Secret                string `json:"-"`
+	//шаг последнего принятого кода, повторно код того же шага не принимается
+	LastCounter int64 `json:"-"`
+	Locked      bool  `json:"-"`
+}
+
+// Данные для подключения приложения-аутентификатора
+type Enrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+var (
+	ErrNotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrInvalidCode    = errors.New("invalid two-factor code")
+	ErrLocked         = errors.New("too many invalid two-factor codes")
+	ErrRequired       = errors.New("two-factor code required")
+)
+
+// Резервный код вида xxxxx-xxxxx
+func GenerateRecoveryCode() (string, error) {
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	s := hex.EncodeToString(buf)
+	return s[:5] + "-" + s[5:], nil
+}
+
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
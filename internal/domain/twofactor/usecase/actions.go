@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/domain/twofactor"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/totp"
+)
+
+type twoFactorUsecase struct {
+	twoFactorRepo TwoFactorRepo
+	userRepo      UserRepo
+	transactor    Transactor
+	issuer        string
+	logger        logger.Logger
+}
+
+func New(tr TwoFactorRepo, ur UserRepo, t Transactor, issuer string, l logger.Logger) *twoFactorUsecase {
+	return &twoFactorUsecase{
+		twoFactorRepo: tr,
+		userRepo:      ur,
+		transactor:    t,
+		issuer:        issuer,
+		logger:        l,
+	}
+}
+
+func (t *twoFactorUsecase) GetSettings(ctx context.Context, userid string) (*twofactor.Settings, error) {
+	s, err := t.twoFactorRepo.Get(ctx, userid)
+	if errors.Is(err, twofactor.ErrNotEnrolled) {
+		return &twofactor.Settings{}, nil
+	}
+	return s, err
+}
+
+func (t *twoFactorUsecase) IsEnabled(ctx context.Context, userid string) (bool, error) {
+	s, err := t.GetSettings(ctx, userid)
+	if err != nil {
+		return false, err
+	}
+	return s.Enabled, nil
+}
+
+// Выдает новый секрет. 2FA включается только после подтверждения кодом из приложения
+func (t *twoFactorUsecase) Enroll(ctx context.Context, userid string) (*twofactor.Enrollment, error) {
+	usr, err := t.userRepo.GetUserByID(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := t.IsEnabled(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		return nil, twofactor.ErrAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.twoFactorRepo.SavePending(ctx, userid, secret); err != nil {
+		return nil, err
+	}
+
+	return &twofactor.Enrollment{Secret: secret, URI: totp.URI(t.issuer, usr.Login, secret)}, nil
+}
+
+// Включает 2FA по первому коду из приложения и возвращает резервные коды. Коды показываются один раз
+func (t *twoFactorUsecase) Confirm(ctx context.Context, userid string, code string) ([]string, error) {
+	var codes []string
+	err := t.check(ctx, userid, code, true, func(ctx context.Context) error {
+		if err := t.twoFactorRepo.Enable(ctx, userid); err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = t.replaceRecoveryCodes(ctx, userid)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Проверяет код из приложения или резервный код
+func (t *twoFactorUsecase) Verify(ctx context.Context, userid string, code string) error {
+	return t.check(ctx, userid, code, false, nil)
+}
+
+func (t *twoFactorUsecase) Disable(ctx context.Context, userid string, code string) error {
+	return t.check(ctx, userid, code, false, func(ctx context.Context) error {
+		return t.twoFactorRepo.Delete(ctx, userid)
+	})
+}
+
+func (t *twoFactorUsecase) SetRequireForWithdrawals(ctx context.Context, userid string, required bool, code string) error {
+	return t.check(ctx, userid, code, false, func(ctx context.Context) error {
+		return t.twoFactorRepo.SetRequireForWithdrawals(ctx, userid, required)
+	})
+}
+
+func (t *twoFactorUsecase) RegenerateRecoveryCodes(ctx context.Context, userid string, code string) ([]string, error) {
+	var codes []string
+	err := t.check(ctx, userid, code, false, func(ctx context.Context) error {
+		var err error
+		codes, err = t.replaceRecoveryCodes(ctx, userid)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Проверка перед списанием: код нужен, только если пользователь включил это требование
+func (t *twoFactorUsecase) CheckWithdrawal(ctx context.Context, userid string, code string) error {
+	s, err := t.GetSettings(ctx, userid)
+	if err != nil {
+		return err
+	}
+
+	if !s.Enabled || !s.RequireForWithdrawals {
+		return nil
+	}
+
+	if code == "" {
+		return twofactor.ErrRequired
+	}
+
+	return t.Verify(ctx, userid, code)
+}
+
+// Проверяет код и при успехе выполняет onSuccess в той же транзакции.
+// pending - проверка кода неподтвержденного секрета, резервные коды при этом не принимаются.
+// Неудачная попытка сохраняется, поэтому ошибка неверного кода возвращается после фиксации транзакции
+func (t *twoFactorUsecase) check(ctx context.Context, userid string, code string, pending bool, onSuccess func(ctx context.Context) error) error {
+	var rejected error
+
+	err := t.transactor.Within(ctx, func(ctx context.Context) error {
+		s, err := t.twoFactorRepo.GetForUpdate(ctx, userid)
+		if err != nil {
+			return err
+		}
+
+		if pending && s.Enabled {
+			return twofactor.ErrAlreadyEnabled
+		}
+
+		if !pending && !s.Enabled {
+			return twofactor.ErrNotEnabled
+		}
+
+		if s.Locked {
+			rejected = twofactor.ErrLocked
+			return nil
+		}
+
+		counter, ok := totp.Verify(s.Secret, code, time.Now(), twofactor.Skew)
+		if ok && counter > s.LastCounter {
+			if err := t.twoFactorRepo.Accept(ctx, userid, counter); err != nil {
+				return err
+			}
+			return t.onSuccess(ctx, onSuccess)
+		}
+
+		if !pending {
+			used, err := t.twoFactorRepo.UseRecoveryCode(ctx, userid, token.Hash(twofactor.NormalizeRecoveryCode(code)))
+			if err != nil {
+				return err
+			}
+
+			if used {
+				t.logger.Infoln("[2FA RECOVERY CODE USED]", userid)
+				if err := t.twoFactorRepo.Accept(ctx, userid, s.LastCounter); err != nil {
+					return err
+				}
+				return t.onSuccess(ctx, onSuccess)
+			}
+		}
+
+		rejected = twofactor.ErrInvalidCode
+		t.logger.Infow("[SECURITY] invalid two-factor code", "userid", userid)
+		return t.twoFactorRepo.Fail(ctx, userid, twofactor.MaxFailures, twofactor.LockDuration)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return rejected
+}
+
+func (t *twoFactorUsecase) onSuccess(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx)
+}
+
+func (t *twoFactorUsecase) replaceRecoveryCodes(ctx context.Context, userid string) ([]string, error) {
+	codes := make([]string, 0, twofactor.RecoveryCodes)
+	hashes := make([]string, 0, twofactor.RecoveryCodes)
+
+	for i := 0; i < twofactor.RecoveryCodes; i++ {
+		code, err := twofactor.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, token.Hash(code))
+	}
+
+	if err := t.twoFactorRepo.ReplaceRecoveryCodes(ctx, userid, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,223 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/twofactor/usecase (interfaces: TwoFactorRepo,UserRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/twofactor/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/twofactor/usecase TwoFactorRepo,UserRepo
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	twofactor "github.com/benderr/gophermart/internal/domain/twofactor"
+	user "github.com/benderr/gophermart/internal/domain/user"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTwoFactorRepo is a mock of TwoFactorRepo interface.
+type MockTwoFactorRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorRepoMockRecorder
+}
+
+// MockTwoFactorRepoMockRecorder is the mock recorder for MockTwoFactorRepo.
+type MockTwoFactorRepoMockRecorder struct {
+	mock *MockTwoFactorRepo
+}
+
+// NewMockTwoFactorRepo creates a new mock instance.
+func NewMockTwoFactorRepo(ctrl *gomock.Controller) *MockTwoFactorRepo {
+	mock := &MockTwoFactorRepo{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorRepo) EXPECT() *MockTwoFactorRepoMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockTwoFactorRepo) Accept(arg0 context.Context, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockTwoFactorRepoMockRecorder) Accept(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockTwoFactorRepo)(nil).Accept), arg0, arg1, arg2)
+}
+
+// Delete mocks base method.
+func (m *MockTwoFactorRepo) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTwoFactorRepoMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTwoFactorRepo)(nil).Delete), arg0, arg1)
+}
+
+// Enable mocks base method.
+func (m *MockTwoFactorRepo) Enable(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockTwoFactorRepoMockRecorder) Enable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockTwoFactorRepo)(nil).Enable), arg0, arg1)
+}
+
+// Fail mocks base method.
+func (m *MockTwoFactorRepo) Fail(arg0 context.Context, arg1 string, arg2 int, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockTwoFactorRepoMockRecorder) Fail(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockTwoFactorRepo)(nil).Fail), arg0, arg1, arg2, arg3)
+}
+
+// Get mocks base method.
+func (m *MockTwoFactorRepo) Get(arg0 context.Context, arg1 string) (*twofactor.Settings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*twofactor.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTwoFactorRepoMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTwoFactorRepo)(nil).Get), arg0, arg1)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockTwoFactorRepo) GetForUpdate(arg0 context.Context, arg1 string) (*twofactor.Settings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(*twofactor.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockTwoFactorRepoMockRecorder) GetForUpdate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockTwoFactorRepo)(nil).GetForUpdate), arg0, arg1)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockTwoFactorRepo) ReplaceRecoveryCodes(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockTwoFactorRepoMockRecorder) ReplaceRecoveryCodes(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockTwoFactorRepo)(nil).ReplaceRecoveryCodes), arg0, arg1, arg2)
+}
+
+// SavePending mocks base method.
+func (m *MockTwoFactorRepo) SavePending(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePending", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePending indicates an expected call of SavePending.
+func (mr *MockTwoFactorRepoMockRecorder) SavePending(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePending", reflect.TypeOf((*MockTwoFactorRepo)(nil).SavePending), arg0, arg1, arg2)
+}
+
+// SetRequireForWithdrawals mocks base method.
+func (m *MockTwoFactorRepo) SetRequireForWithdrawals(arg0 context.Context, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRequireForWithdrawals", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRequireForWithdrawals indicates an expected call of SetRequireForWithdrawals.
+func (mr *MockTwoFactorRepoMockRecorder) SetRequireForWithdrawals(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRequireForWithdrawals", reflect.TypeOf((*MockTwoFactorRepo)(nil).SetRequireForWithdrawals), arg0, arg1, arg2)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTwoFactorRepo) UseRecoveryCode(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTwoFactorRepoMockRecorder) UseRecoveryCode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTwoFactorRepo)(nil).UseRecoveryCode), arg0, arg1, arg2)
+}
+
+// MockUserRepo is a mock of UserRepo interface.
+type MockUserRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepoMockRecorder
+}
+
+// MockUserRepoMockRecorder is the mock recorder for MockUserRepo.
+type MockUserRepoMockRecorder struct {
+	mock *MockUserRepo
+}
+
+// NewMockUserRepo creates a new mock instance.
+func NewMockUserRepo(ctrl *gomock.Controller) *MockUserRepo {
+	mock := &MockUserRepo{ctrl: ctrl}
+	mock.recorder = &MockUserRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
+	return m.recorder
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserRepo) GetUserByID(arg0 context.Context, arg1 string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepoMockRecorder) GetUserByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepo)(nil).GetUserByID), arg0, arg1)
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/twofactor"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type TwoFactorRepo interface {
+	Get(ctx context.Context, userid string) (*twofactor.Settings, error)
+	GetForUpdate(ctx context.Context, userid string) (*twofactor.Settings, error)
+	SavePending(ctx context.Context, userid string, secret string) error
+	Enable(ctx context.Context, userid string) error
+	Delete(ctx context.Context, userid string) error
+	Accept(ctx context.Context, userid string, counter int64) error
+	Fail(ctx context.Context, userid string, maxFailures int, lock time.Duration) error
+	SetRequireForWithdrawals(ctx context.Context, userid string, required bool) error
+	ReplaceRecoveryCodes(ctx context.Context, userid string, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userid string, hash string) (bool, error)
+}
+
+type UserRepo interface {
+	GetUserByID(ctx context.Context, id string) (*user.User, error)
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/domain/twofactor"
+	"github.com/benderr/gophermart/internal/domain/twofactor/usecase"
+	"github.com/benderr/gophermart/internal/domain/twofactor/usecase/mocks"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	"github.com/benderr/gophermart/internal/totp"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepo(ctrl)
+	mockUserRepo := mocks.NewMockUserRepo(ctrl)
+	twoFactorUsecase := usecase.New(mockRepo, mockUserRepo, mocktransactor.New(), "Gophermart", mocklogger.New())
+
+	userid := "testuserid"
+
+	t.Run("Valid code", func(t *testing.T) {
+		now := time.Now()
+		code, _ := totp.Code(secret, now)
+
+		mockRepo.EXPECT().GetForUpdate(gomock.Any(), userid).Return(&twofactor.Settings{Enabled: true, Secret: secret}, nil)
+		mockRepo.EXPECT().Accept(gomock.Any(), userid, gomock.Any()).Return(nil)
+
+		assert.NoError(t, twoFactorUsecase.Verify(context.Background(), userid, code))
+	})
+
+	t.Run("Replayed code", func(t *testing.T) {
+		now := time.Now()
+		code, _ := totp.Code(secret, now)
+
+		mockRepo.EXPECT().GetForUpdate(gomock.Any(), userid).Return(&twofactor.Settings{Enabled: true, Secret: secret, LastCounter: totp.Counter(now) + 1}, nil)
+		mockRepo.EXPECT().UseRecoveryCode(gomock.Any(), userid, gomock.Any()).Return(false, nil)
+		mockRepo.EXPECT().Fail(gomock.Any(), userid, twofactor.MaxFailures, twofactor.LockDuration).Return(nil)
+
+		err := twoFactorUsecase.Verify(context.Background(), userid, code)
+		assert.Equal(t, twofactor.ErrInvalidCode, err)
+	})
+
+	t.Run("Recovery code", func(t *testing.T) {
+		mockRepo.EXPECT().GetForUpdate(gomock.Any(), userid).Return(&twofactor.Settings{Enabled: true, Secret: secret, LastCounter: 7}, nil)
+		mockRepo.EXPECT().UseRecoveryCode(gomock.Any(), userid, token.Hash("abcde-12345")).Return(true, nil)
+		mockRepo.EXPECT().Accept(gomock.Any(), userid, int64(7)).Return(nil)
+
+		assert.NoError(t, twoFactorUsecase.Verify(context.Background(), userid, " ABCDE-12345 "))
+	})
+
+	t.Run("Locked", func(t *testing.T) {
+		mockRepo.EXPECT().GetForUpdate(gomock.Any(), userid).Return(&twofactor.Settings{Enabled: true, Secret: secret, Locked: true}, nil)
+
+		err := twoFactorUsecase.Verify(context.Background(), userid, "123456")
+		assert.Equal(t, twofactor.ErrLocked, err)
+	})
+}
+
+func TestCheckWithdrawal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepo(ctrl)
+	mockUserRepo := mocks.NewMockUserRepo(ctrl)
+	twoFactorUsecase := usecase.New(mockRepo, mockUserRepo, mocktransactor.New(), "Gophermart", mocklogger.New())
+
+	userid := "testuserid"
+
+	t.Run("Not enrolled", func(t *testing.T) {
+		mockRepo.EXPECT().Get(gomock.Any(), userid).Return(nil, twofactor.ErrNotEnrolled)
+
+		assert.NoError(t, twoFactorUsecase.CheckWithdrawal(context.Background(), userid, ""))
+	})
+
+	t.Run("Code required", func(t *testing.T) {
+		mockRepo.EXPECT().Get(gomock.Any(), userid).Return(&twofactor.Settings{Enabled: true, RequireForWithdrawals: true}, nil)
+
+		err := twoFactorUsecase.CheckWithdrawal(context.Background(), userid, "")
+		assert.Equal(t, twofactor.ErrRequired, err)
+	})
+}
@@ -5,24 +5,71 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	"github.com/benderr/gophermart/internal/domain/referral"
+	"github.com/benderr/gophermart/internal/domain/tier"
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/domain/twofactor"
 	"github.com/benderr/gophermart/internal/domain/user"
 	"github.com/benderr/gophermart/internal/httputils"
 	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/password"
 	"github.com/labstack/echo/v4"
 )
 
 type UserUsecase interface {
-	Login(ctx context.Context, login, password string) (*user.User, error)
-	Register(ctx context.Context, login, password string) (*user.User, error)
+	Login(ctx context.Context, login, password, ip string) (*user.User, error)
+	LoginSecondFactor(ctx context.Context, userid, code string) (*user.User, error)
+	Register(ctx context.Context, login, password, referralCode, ip string) (*user.User, error)
+	ChangePassword(ctx context.Context, userid, oldPassword, newPassword string) error
+	RequestReset(ctx context.Context, login string) error
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
+}
+
+type UserAdminUsecase interface {
+	GetByID(ctx context.Context, id string) (*user.User, error)
+	GetByLogin(ctx context.Context, login string) (*user.User, error)
+	SetRole(ctx context.Context, actor string, id string, role user.Role) error
+}
+
+type ProfileUsecase interface {
+	GetProfile(ctx context.Context, userid string) (*user.Profile, error)
+	UpdateProfile(ctx context.Context, userid string, p user.ProfileUpdate) (*user.Profile, error)
+}
+
+// Уровень и баланс для сводки в профиле
+type Tiers interface {
+	GetStatus(ctx context.Context, userid string) (*tier.Status, error)
+}
+
+type Balances interface {
+	GetBalanceByUser(ctx context.Context, userid string) (*balance.Balance, error)
 }
 
 type SessionManager interface {
-	Create(userID string) (string, error)
+	Issue(ctx context.Context, userid string) (*token.Pair, error)
+}
+
+type Transport interface {
+	Respond(c echo.Context, pair *token.Pair) error
+}
+
+// Токен между проверкой пароля и кодом 2FA
+type PreAuth interface {
+	CreatePreAuth(userID string) (string, error)
+	ParsePreAuth(raw string) (string, error)
+}
+
+type Auth interface {
+	GetUserID(c echo.Context) (string, error)
 }
 
 type userHandler struct {
-	session SessionManager
-	logger  logger.Logger
+	session   SessionManager
+	preAuth   PreAuth
+	transport Transport
+	logger    logger.Logger
 	UserUsecase
 }
 
@@ -31,21 +78,160 @@ type User struct {
 	Password string `json:"password" validate:"required"`
 }
 
-func NewUserHandlers(e *echo.Group, uu UserUsecase, session SessionManager, logger logger.Logger) {
+type RegisterModel struct {
+	User
+	ReferralCode string `json:"referral_code"`
+}
+
+type SecondFactorModel struct {
+	PreAuthToken string `json:"pre_auth_token" validate:"required"`
+	Code         string `json:"code" validate:"required"`
+}
+
+type PreAuthResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	PreAuthToken      string `json:"pre_auth_token"`
+}
+
+type ChangePasswordModel struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+type ResetRequestModel struct {
+	Login string `json:"login" validate:"required"`
+}
+
+type RoleModel struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// Пустая строка очищает поле, отсутствующее поле не меняется
+type ProfileModel struct {
+	DisplayName   *string             `json:"display_name" validate:"omitempty,max=100"`
+	Email         *string             `json:"email" validate:"omitempty,max=254,len=0|email"`
+	Phone         *string             `json:"phone" validate:"omitempty,len=0|e164"`
+	Notifications *NotificationsModel `json:"notifications"`
+}
+
+type NotificationsModel struct {
+	Email *bool `json:"email"`
+	SMS   *bool `json:"sms"`
+	Promo *bool `json:"promo"`
+}
+
+type BalanceSummary struct {
+	Current   float64 `json:"current"`
+	Available float64 `json:"available"`
+	Withdrawn float64 `json:"withdrawn"`
+}
+
+type ProfileResponse struct {
+	*user.Profile
+	//nil, если уровни отключены
+	Tier    *tier.Status    `json:"tier,omitempty"`
+	Balance *BalanceSummary `json:"balance"`
+}
+
+type ResetModel struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+func NewUserHandlers(e *echo.Group, uu UserUsecase, session SessionManager, preAuth PreAuth, transport Transport, logger logger.Logger) {
 	h := &userHandler{
 		UserUsecase: uu,
 		session:     session,
+		preAuth:     preAuth,
+		transport:   transport,
 		logger:      logger,
 	}
 
 	group := e.Group("/api/user")
 
 	group.POST("/login", h.LoginHandler)
+	group.POST("/login/2fa", h.SecondFactorHandler)
 	group.POST("/register", h.RegisterHandler)
+	group.POST("/password/reset", h.RequestResetHandler)
+	group.POST("/password/reset/confirm", h.ResetPasswordHandler)
+}
+
+type accountHandler struct {
+	auth      Auth
+	session   SessionManager
+	transport Transport
+	logger    logger.Logger
+	UserUsecase
+}
+
+// Обработчики для авторизованного пользователя
+func NewAccountHandlers(e *echo.Group, uu UserUsecase, auth Auth, session SessionManager, transport Transport, logger logger.Logger) {
+	h := &accountHandler{
+		UserUsecase: uu,
+		auth:        auth,
+		session:     session,
+		transport:   transport,
+		logger:      logger,
+	}
+
+	group := e.Group("/api/user")
+
+	group.POST("/password", h.ChangePasswordHandler)
+}
+
+type profileHandler struct {
+	auth     Auth
+	tiers    Tiers
+	balances Balances
+	logger   logger.Logger
+	ProfileUsecase
+}
+
+func NewProfileHandlers(e *echo.Group, pu ProfileUsecase, auth Auth, tiers Tiers, balances Balances, logger logger.Logger) {
+	h := &profileHandler{
+		ProfileUsecase: pu,
+		auth:           auth,
+		tiers:          tiers,
+		balances:       balances,
+		logger:         logger,
+	}
+
+	group := e.Group("/api/user")
+
+	group.GET("/me", h.GetProfileHandler)
+	group.PATCH("/me", h.UpdateProfileHandler)
+}
+
+type userAdminHandler struct {
+	auth   Auth
+	logger logger.Logger
+	UserAdminUsecase
+}
+
+// Поиск пользователей доступен поддержке, поэтому регистрируется в группе /api/admin для поддержки и администраторов
+func NewUserSupportHandlers(group *echo.Group, uu UserAdminUsecase, logger logger.Logger) {
+	h := &userAdminHandler{
+		UserAdminUsecase: uu,
+		logger:           logger,
+	}
+
+	group.GET("/users", h.FindUserHandler)
+	group.GET("/users/:id", h.GetUserHandler)
+}
+
+// Регистрирует обработчики в группе /api/admin, доступ к которой уже ограничен администраторами
+func NewUserAdminHandlers(group *echo.Group, uu UserAdminUsecase, auth Auth, logger logger.Logger) {
+	h := &userAdminHandler{
+		UserAdminUsecase: uu,
+		auth:             auth,
+		logger:           logger,
+	}
+
+	group.PUT("/users/:id/role", h.SetRoleHandler)
 }
 
 func (u *userHandler) RegisterHandler(c echo.Context) error {
-	var usr User
+	var usr RegisterModel
 
 	if err := c.Bind(&usr); err != nil {
 		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
@@ -55,26 +241,32 @@ func (u *userHandler) RegisterHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
 	}
 
-	created, err := u.Register(c.Request().Context(), usr.Login, usr.Password)
+	created, err := u.Register(c.Request().Context(), usr.Login, usr.Password, usr.ReferralCode, c.RealIP())
 
 	if err != nil {
+		var busy *password.BusyError
+		if errors.As(err, &busy) {
+			return busyResponse(c, busy)
+		}
+
 		if errors.Is(err, user.ErrLoginExist) {
 			return c.JSON(http.StatusConflict, httputils.Error("already exist"))
 		}
+		if errors.Is(err, referral.ErrInvalidCode) || errors.Is(err, referral.ErrSelfReferral) || errors.Is(err, referral.ErrTooMany) {
+			return c.JSON(http.StatusBadRequest, httputils.Error(err.Error()))
+		}
 		u.logger.Errorln(err)
 		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
 	}
 
-	sessionKey, err := u.session.Create(created.ID)
+	pair, err := u.session.Issue(c.Request().Context(), created.ID)
 
 	if err != nil {
 		u.logger.Errorln(err)
 		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
 	}
 
-	c.Response().Header().Add("Authorization", "Bearer "+sessionKey)
-
-	return c.JSON(http.StatusOK, httputils.Ok())
+	return u.transport.Respond(c, pair)
 }
 
 func (u *userHandler) LoginHandler(c echo.Context) error {
@@ -88,11 +280,22 @@ func (u *userHandler) LoginHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
 	}
 
-	existUser, err := u.Login(c.Request().Context(), usr.Login, usr.Password)
+	existUser, err := u.Login(c.Request().Context(), usr.Login, usr.Password, c.RealIP())
 
 	if err != nil {
 		u.logger.Errorln("ERROR", err)
 
+		var busy *password.BusyError
+		if errors.As(err, &busy) {
+			return busyResponse(c, busy)
+		}
+
+		var locked *lockout.LockedError
+		if errors.As(err, &locked) {
+			c.Response().Header().Set("Retry-After", httputils.RetryAfter(locked.RetryAfter))
+			return c.JSON(http.StatusTooManyRequests, httputils.Error(err.Error()))
+		}
+
 		if errors.Is(err, user.ErrNotFound) || errors.Is(err, user.ErrBadPass) {
 			return c.JSON(http.StatusUnauthorized, httputils.Error("user not found"))
 		}
@@ -100,14 +303,298 @@ func (u *userHandler) LoginHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
 	}
 
-	sessionKey, err := u.session.Create(existUser.ID)
+	if existUser.TwoFactor {
+		return u.preAuthResponse(c, existUser.ID)
+	}
+
+	pair, err := u.session.Issue(c.Request().Context(), existUser.ID)
 
 	if err != nil {
 		u.logger.Errorln(err)
 		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
 	}
 
-	c.Response().Header().Add("Authorization", "Bearer "+sessionKey)
+	return u.transport.Respond(c, pair)
+}
+
+// Пул хеширования паролей переполнен, клиент повторит запрос позже
+func busyResponse(c echo.Context, busy *password.BusyError) error {
+	c.Response().Header().Set("Retry-After", httputils.RetryAfter(busy.RetryAfter))
+	return c.JSON(http.StatusServiceUnavailable, httputils.Error("service busy"))
+}
+
+// Пароль проверен, но токены выдаются только после кода 2FA
+func (u *userHandler) preAuthResponse(c echo.Context, userid string) error {
+	preAuthToken, err := u.preAuth.CreatePreAuth(userid)
+	if err != nil {
+		u.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, &PreAuthResponse{
+		TwoFactorRequired: true,
+		PreAuthToken:      preAuthToken,
+	})
+}
+
+func (u *userHandler) SecondFactorHandler(c echo.Context) error {
+	var m SecondFactorModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := u.preAuth.ParsePreAuth(m.PreAuthToken)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, httputils.Error("invalid pre-auth token"))
+	}
+
+	existUser, err := u.LoginSecondFactor(c.Request().Context(), userid, m.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, twofactor.ErrInvalidCode):
+			return c.JSON(http.StatusUnauthorized, httputils.Error(err.Error()))
+		case errors.Is(err, twofactor.ErrLocked):
+			return c.JSON(http.StatusTooManyRequests, httputils.Error(err.Error()))
+		}
+		u.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	pair, err := u.session.Issue(c.Request().Context(), existUser.ID)
+	if err != nil {
+		u.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return u.transport.Respond(c, pair)
+}
+
+func (u *userHandler) RequestResetHandler(c echo.Context) error {
+	var m ResetRequestModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := u.RequestReset(c.Request().Context(), m.Login); err != nil {
+		u.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusAccepted, httputils.Ok())
+}
+
+func (u *userHandler) ResetPasswordHandler(c echo.Context) error {
+	var m ResetModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := u.ResetPassword(c.Request().Context(), m.Token, m.Password); err != nil {
+		var busy *password.BusyError
+		if errors.As(err, &busy) {
+			return busyResponse(c, busy)
+		}
+
+		if errors.Is(err, user.ErrInvalidResetToken) {
+			return c.JSON(http.StatusBadRequest, httputils.Error(err.Error()))
+		}
+		u.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, httputils.Ok())
+}
+
+// После смены пароля все сессии отозваны, текущему клиенту выдаем новую пару токенов
+func (a *accountHandler) ChangePasswordHandler(c echo.Context) error {
+	var m ChangePasswordModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := a.auth.GetUserID(c)
+	if err != nil {
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if err := a.ChangePassword(c.Request().Context(), userid, m.OldPassword, m.NewPassword); err != nil {
+		var busy *password.BusyError
+		if errors.As(err, &busy) {
+			return busyResponse(c, busy)
+		}
+
+		if errors.Is(err, user.ErrBadPass) {
+			return c.JSON(http.StatusForbidden, httputils.Error("invalid password"))
+		}
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	pair, err := a.session.Issue(c.Request().Context(), userid)
+	if err != nil {
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return a.transport.Respond(c, pair)
+}
+
+// Поиск пользователя по логину: /api/admin/users?login=<логин>
+func (a *userAdminHandler) FindUserHandler(c echo.Context) error {
+	login := c.QueryParam("login")
+	if login == "" {
+		return c.JSON(http.StatusBadRequest, httputils.Error("login is required"))
+	}
+
+	found, err := a.GetByLogin(c.Request().Context(), login)
+	return a.userResponse(c, found, err)
+}
+
+func (a *userAdminHandler) GetUserHandler(c echo.Context) error {
+	found, err := a.GetByID(c.Request().Context(), c.Param("id"))
+	return a.userResponse(c, found, err)
+}
+
+func (a *userAdminHandler) userResponse(c echo.Context, found *user.User, err error) error {
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, httputils.Error(err.Error()))
+		}
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, found)
+}
+
+func (a *userAdminHandler) SetRoleHandler(c echo.Context) error {
+	var m RoleModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	role, err := user.ParseRole(m.Role)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error(err.Error()))
+	}
+
+	actor, err := a.auth.GetUserID(c)
+	if err != nil {
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if err := a.SetRole(c.Request().Context(), actor, c.Param("id"), role); err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, httputils.Error(err.Error()))
+		}
+		a.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
 
 	return c.JSON(http.StatusOK, httputils.Ok())
 }
+
+func (p *profileHandler) GetProfileHandler(c echo.Context) error {
+	userid, err := p.auth.GetUserID(c)
+	if err != nil {
+		p.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	profile, err := p.GetProfile(c.Request().Context(), userid)
+	if err != nil {
+		p.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return p.respond(c, profile)
+}
+
+func (p *profileHandler) UpdateProfileHandler(c echo.Context) error {
+	var m ProfileModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := p.auth.GetUserID(c)
+	if err != nil {
+		p.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	upd := user.ProfileUpdate{
+		DisplayName: m.DisplayName,
+		Email:       m.Email,
+		Phone:       m.Phone,
+	}
+	if m.Notifications != nil {
+		upd.NotifyEmail = m.Notifications.Email
+		upd.NotifySMS = m.Notifications.SMS
+		upd.NotifyPromo = m.Notifications.Promo
+	}
+
+	profile, err := p.UpdateProfile(c.Request().Context(), userid, upd)
+	if err != nil {
+		p.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return p.respond(c, profile)
+}
+
+// Дополняет профиль текущим уровнем и сводкой по балансу
+func (p *profileHandler) respond(c echo.Context, profile *user.Profile) error {
+	resp := &ProfileResponse{Profile: profile}
+
+	st, err := p.tiers.GetStatus(c.Request().Context(), profile.ID)
+	if err != nil && !errors.Is(err, tier.ErrDisabled) {
+		p.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+	resp.Tier = st
+
+	bal, err := p.balances.GetBalanceByUser(c.Request().Context(), profile.ID)
+	if err != nil {
+		p.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+	resp.Balance = &BalanceSummary{
+		Current:   bal.Current,
+		Available: bal.Available,
+		Withdrawn: bal.Withdrawn,
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
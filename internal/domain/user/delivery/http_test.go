@@ -5,11 +5,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/benderr/gophermart/internal/domain/balance"
+	"github.com/benderr/gophermart/internal/domain/lockout"
+	"github.com/benderr/gophermart/internal/domain/tier"
+	"github.com/benderr/gophermart/internal/domain/token"
 	"github.com/benderr/gophermart/internal/domain/user"
 	"github.com/benderr/gophermart/internal/domain/user/delivery"
 	"github.com/benderr/gophermart/internal/domain/user/delivery/mocks"
 	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	"github.com/benderr/gophermart/internal/password"
+	"github.com/benderr/gophermart/internal/session"
 	"github.com/go-playground/validator"
 	"github.com/go-resty/resty/v2"
 	"github.com/labstack/echo/v4"
@@ -28,16 +35,18 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return nil
 }
 
-func newTestServer(mockUsecase delivery.UserUsecase, mockSession delivery.SessionManager) *httptest.Server {
+func newTestServer(mockUsecase delivery.UserUsecase, mockSession delivery.SessionManager, transport delivery.Transport) *httptest.Server {
 	e := echo.New()
 	e.Validator = &CustomValidator{validator: validator.New()}
 
 	mockLogger := mocklogger.New()
-	delivery.NewUserHandlers(e.Group(""), mockUsecase, mockSession, mockLogger)
+	delivery.NewUserHandlers(e.Group(""), mockUsecase, mockSession, preAuth, transport, mockLogger)
 
 	return httptest.NewServer(e)
 }
 
+var preAuth = session.New("secret", nil, time.Minute, time.Time{})
+
 func newRequest(baseServer string, login, pass string) *resty.Request {
 	return resty.New().SetBaseURL(baseServer).R().SetHeader(echo.HeaderContentType, echo.MIMEApplicationJSON)
 }
@@ -49,37 +58,91 @@ func TestLogin(t *testing.T) {
 	mockUsecase := mocks.NewMockUserUsecase(ctrl)
 	mockSession := mocks.NewMockSessionManager(ctrl)
 
-	server := newTestServer(mockUsecase, mockSession)
+	server := newTestServer(mockUsecase, mockSession, session.NewHeaderTransport())
 	defer server.Close()
 
 	t.Run("Login success", func(t *testing.T) {
 		login := "login"
 		pass := "123"
 		userid := "testuserid"
-		token := "jwttoken"
+		accessToken := "jwttoken"
+		refreshToken := "refreshtoken"
 
-		mockUsecase.EXPECT().Login(gomock.Any(), login, pass).Return(&user.User{
+		mockUsecase.EXPECT().Login(gomock.Any(), login, pass, gomock.Any()).Return(&user.User{
 			Login: login,
 			ID:    userid,
 		}, nil)
 
-		mockSession.EXPECT().Create(userid).Return(token, nil)
+		mockSession.EXPECT().Issue(gomock.Any(), userid).Return(&token.Pair{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: 900}, nil)
+
+		resp, err := newRequest(server.URL, login, pass).
+			SetBody(fmt.Sprintf(`{"login":"%v","password":"%v"}`, login, pass)).
+			Post("/api/user/login")
+
+		assert.NoError(t, err, "error making HTTP request")
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Equal(t, "Bearer "+accessToken, resp.Header().Get("Authorization"))
+		assert.JSONEq(t, `{"refresh_token":"refreshtoken","expires_in":900}`, string(resp.Body()))
+	})
+
+	t.Run("Two-factor required", func(t *testing.T) {
+		login := "login"
+		pass := "123"
+		userid := "testuserid"
+
+		mockUsecase.EXPECT().Login(gomock.Any(), login, pass, gomock.Any()).Return(&user.User{
+			Login:     login,
+			ID:        userid,
+			TwoFactor: true,
+		}, nil)
 
+		var body delivery.PreAuthResponse
 		resp, err := newRequest(server.URL, login, pass).
 			SetBody(fmt.Sprintf(`{"login":"%v","password":"%v"}`, login, pass)).
+			SetResult(&body).
 			Post("/api/user/login")
 
 		assert.NoError(t, err, "error making HTTP request")
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode())
-		assert.Equal(t, "Bearer "+token, resp.Header().Get("Authorization"))
+		assert.Empty(t, resp.Header().Get("Authorization"))
+		assert.True(t, body.TwoFactorRequired)
+
+		mockUsecase.EXPECT().LoginSecondFactor(gomock.Any(), userid, "123456").Return(&user.User{Login: login, ID: userid}, nil)
+		mockSession.EXPECT().Issue(gomock.Any(), userid).Return(&token.Pair{AccessToken: "jwttoken", ExpiresIn: 900}, nil)
+
+		resp, err = newRequest(server.URL, login, pass).
+			SetBody(fmt.Sprintf(`{"pre_auth_token":"%v","code":"123456"}`, body.PreAuthToken)).
+			Post("/api/user/login/2fa")
+
+		assert.NoError(t, err, "error making HTTP request")
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Equal(t, "Bearer jwttoken", resp.Header().Get("Authorization"))
+	})
+
+	t.Run("Hashing busy", func(t *testing.T) {
+		login := "login"
+		pass := "123"
+
+		mockUsecase.EXPECT().Login(gomock.Any(), login, pass, gomock.Any()).Return(nil, &password.BusyError{RetryAfter: 2 * time.Second})
+
+		resp, err := newRequest(server.URL, login, pass).
+			SetBody(fmt.Sprintf(`{"login":"%v","password":"%v"}`, login, pass)).
+			Post("/api/user/login")
+
+		assert.NoError(t, err, "error making HTTP request")
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+		assert.Equal(t, "2", resp.Header().Get("Retry-After"))
 	})
 
 	t.Run("Bad pass", func(t *testing.T) {
 		login := "login"
 		pass := "123222"
 
-		mockUsecase.EXPECT().Login(gomock.Any(), login, pass).Return(&user.User{
+		mockUsecase.EXPECT().Login(gomock.Any(), login, pass, gomock.Any()).Return(&user.User{
 			Login: "",
 			ID:    "",
 		}, user.ErrBadPass)
@@ -94,6 +157,22 @@ func TestLogin(t *testing.T) {
 		assert.JSONEq(t, string(resp.Body()), `{"message":"user not found"}`)
 	})
 
+	t.Run("Too many attempts", func(t *testing.T) {
+		login := "login"
+		pass := "123222"
+
+		mockUsecase.EXPECT().Login(gomock.Any(), login, pass, gomock.Any()).Return(nil, &lockout.LockedError{RetryAfter: 1500 * time.Millisecond})
+
+		resp, err := newRequest(server.URL, login, pass).
+			SetBody(fmt.Sprintf(`{"login":"%v","password":"%v"}`, login, pass)).
+			Post("/api/user/login")
+
+		assert.NoError(t, err, "error making HTTP request")
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode())
+		assert.Equal(t, "2", resp.Header().Get("Retry-After"))
+	})
+
 	t.Run("Invalid contract", func(t *testing.T) {
 		login := "login"
 		pass := ""
@@ -109,6 +188,57 @@ func TestLogin(t *testing.T) {
 	})
 }
 
+func TestLoginCookie(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockUserUsecase(ctrl)
+	mockSession := mocks.NewMockSessionManager(ctrl)
+
+	server := newTestServer(mockUsecase, mockSession, session.NewCookieTransport(true, time.Hour))
+	defer server.Close()
+
+	t.Run("Tokens in cookies", func(t *testing.T) {
+		login := "login"
+		pass := "123"
+		userid := "testuserid"
+
+		mockUsecase.EXPECT().Login(gomock.Any(), login, pass, gomock.Any()).Return(&user.User{
+			Login: login,
+			ID:    userid,
+		}, nil)
+
+		mockSession.EXPECT().Issue(gomock.Any(), userid).Return(&token.Pair{AccessToken: "jwttoken", RefreshToken: "refreshtoken", ExpiresIn: 900}, nil)
+
+		resp, err := newRequest(server.URL, login, pass).
+			SetBody(fmt.Sprintf(`{"login":"%v","password":"%v"}`, login, pass)).
+			Post("/api/user/login")
+
+		assert.NoError(t, err, "error making HTTP request")
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Empty(t, resp.Header().Get("Authorization"))
+		assert.JSONEq(t, `{"expires_in":900}`, string(resp.Body()))
+
+		cookies := make(map[string]*http.Cookie)
+		for _, c := range resp.Cookies() {
+			cookies[c.Name] = c
+		}
+
+		if assert.Contains(t, cookies, session.AccessCookie) {
+			assert.Equal(t, "jwttoken", cookies[session.AccessCookie].Value)
+			assert.True(t, cookies[session.AccessCookie].HttpOnly)
+			assert.True(t, cookies[session.AccessCookie].Secure)
+		}
+		if assert.Contains(t, cookies, session.RefreshCookie) {
+			assert.Equal(t, "refreshtoken", cookies[session.RefreshCookie].Value)
+		}
+		if assert.Contains(t, cookies, session.CSRFCookie) {
+			assert.False(t, cookies[session.CSRFCookie].HttpOnly)
+		}
+	})
+}
+
 func TestRegister(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -116,21 +246,22 @@ func TestRegister(t *testing.T) {
 	mockUsecase := mocks.NewMockUserUsecase(ctrl)
 	mockSession := mocks.NewMockSessionManager(ctrl)
 
-	server := newTestServer(mockUsecase, mockSession)
+	server := newTestServer(mockUsecase, mockSession, session.NewHeaderTransport())
 	defer server.Close()
 
 	t.Run("Register success", func(t *testing.T) {
 		login := "login"
 		pass := "123"
 		userid := "testuserid"
-		token := "jwttoken"
+		accessToken := "jwttoken"
+		refreshToken := "refreshtoken"
 
-		mockUsecase.EXPECT().Register(gomock.Any(), login, pass).Return(&user.User{
+		mockUsecase.EXPECT().Register(gomock.Any(), login, pass, "", gomock.Any()).Return(&user.User{
 			Login: login,
 			ID:    userid,
 		}, nil)
 
-		mockSession.EXPECT().Create(userid).Return(token, nil)
+		mockSession.EXPECT().Issue(gomock.Any(), userid).Return(&token.Pair{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: 900}, nil)
 
 		resp, err := newRequest(server.URL, login, pass).
 			SetBody(fmt.Sprintf(`{"login":"%v","password":"%v"}`, login, pass)).
@@ -139,14 +270,15 @@ func TestRegister(t *testing.T) {
 		assert.NoError(t, err, "error making HTTP request")
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode())
-		assert.Equal(t, "Bearer "+token, resp.Header().Get("Authorization"))
+		assert.Equal(t, "Bearer "+accessToken, resp.Header().Get("Authorization"))
+		assert.JSONEq(t, `{"refresh_token":"refreshtoken","expires_in":900}`, string(resp.Body()))
 	})
 
 	t.Run("Already exist", func(t *testing.T) {
 		login := "login"
 		pass := "123222"
 
-		mockUsecase.EXPECT().Register(gomock.Any(), login, pass).Return(&user.User{
+		mockUsecase.EXPECT().Register(gomock.Any(), login, pass, "", gomock.Any()).Return(&user.User{
 			Login: "",
 			ID:    "",
 		}, user.ErrLoginExist)
@@ -175,3 +307,103 @@ func TestRegister(t *testing.T) {
 		assert.JSONEq(t, string(resp.Body()), `{"message":"invalid request payload"}`)
 	})
 }
+
+func TestPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockUserUsecase(ctrl)
+	mockSession := mocks.NewMockSessionManager(ctrl)
+
+	server := newTestServer(mockUsecase, mockSession, session.NewHeaderTransport())
+	defer server.Close()
+
+	t.Run("Request accepted", func(t *testing.T) {
+		mockUsecase.EXPECT().RequestReset(gomock.Any(), "login").Return(nil)
+
+		resp, err := newRequest(server.URL, "", "").
+			SetBody(`{"login":"login"}`).
+			Post("/api/user/password/reset")
+
+		assert.NoError(t, err, "error making HTTP request")
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode())
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		mockUsecase.EXPECT().ResetPassword(gomock.Any(), "resettoken", "newpass").Return(user.ErrInvalidResetToken)
+
+		resp, err := newRequest(server.URL, "", "").
+			SetBody(`{"token":"resettoken","password":"newpass"}`).
+			Post("/api/user/password/reset/confirm")
+
+		assert.NoError(t, err, "error making HTTP request")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+		assert.JSONEq(t, `{"message":"invalid reset token"}`, string(resp.Body()))
+	})
+}
+
+func TestProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfile := mocks.NewMockProfileUsecase(ctrl)
+	mockAuth := mocks.NewMockAuth(ctrl)
+	mockTiers := mocks.NewMockTiers(ctrl)
+	mockBalances := mocks.NewMockBalances(ctrl)
+
+	e := echo.New()
+	e.Validator = &CustomValidator{validator: validator.New()}
+	delivery.NewProfileHandlers(e.Group(""), mockProfile, mockAuth, mockTiers, mockBalances, mocklogger.New())
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	userid := "testuserid"
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Update profile", func(t *testing.T) {
+		email := "user@example.com"
+		sms := true
+
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userid, nil)
+		mockProfile.EXPECT().UpdateProfile(gomock.Any(), userid, user.ProfileUpdate{Email: &email, NotifySMS: &sms}).Return(&user.Profile{
+			ID:            userid,
+			Login:         "login",
+			Role:          user.RoleUser,
+			CreatedAt:     createdAt,
+			Email:         email,
+			Notifications: user.Notifications{Email: true, SMS: true},
+		}, nil)
+		mockTiers.EXPECT().GetStatus(gomock.Any(), userid).Return(nil, tier.ErrDisabled)
+		mockBalances.EXPECT().GetBalanceByUser(gomock.Any(), userid).Return(&balance.Balance{Current: 100, Available: 80, Withdrawn: 20}, nil)
+
+		resp, err := newRequest(server.URL, "", "").
+			SetBody(`{"email":"user@example.com","notifications":{"sms":true}}`).
+			Patch("/api/user/me")
+
+		assert.NoError(t, err, "error making HTTP request")
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.JSONEq(t, `{"id":"testuserid","login":"login","role":"user","created_at":"2024-01-02T03:04:05Z",
+		"display_name":"","email":"user@example.com","phone":"",
+		"notifications":{"email":true,"sms":true,"promo":false},
+		"balance":{"current":100,"available":80,"withdrawn":20}}`, string(resp.Body()))
+	})
+
+	t.Run("Invalid email", func(t *testing.T) {
+		resp, err := newRequest(server.URL, "", "").
+			SetBody(`{"email":"not-an-email"}`).
+			Patch("/api/user/me")
+
+		assert.NoError(t, err, "error making HTTP request")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("Invalid phone", func(t *testing.T) {
+		resp, err := newRequest(server.URL, "", "").
+			SetBody(`{"phone":"8 999 123"}`).
+			Patch("/api/user/me")
+
+		assert.NoError(t, err, "error making HTTP request")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/benderr/gophermart/internal/domain/user/delivery (interfaces: UserUsecase,SessionManager)
+// Source: github.com/benderr/gophermart/internal/domain/user/delivery (interfaces: UserUsecase,SessionManager,ProfileUsecase,Auth,Tiers,Balances)
 //
 // Generated by this command:
 //
-//	mockgen -destination=internal/domain/user/delivery/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/user/delivery UserUsecase,SessionManager
+//	mockgen -destination=internal/domain/user/delivery/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/user/delivery UserUsecase,SessionManager,ProfileUsecase,Auth,Tiers,Balances
 //
 // Package mocks is a generated GoMock package.
 package mocks
@@ -12,7 +12,11 @@ import (
 	context "context"
 	reflect "reflect"
 
+	balance "github.com/benderr/gophermart/internal/domain/balance"
+	tier "github.com/benderr/gophermart/internal/domain/tier"
+	token "github.com/benderr/gophermart/internal/domain/token"
 	user "github.com/benderr/gophermart/internal/domain/user"
+	echo "github.com/labstack/echo/v4"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,34 +43,91 @@ func (m *MockUserUsecase) EXPECT() *MockUserUsecaseMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserUsecase) ChangePassword(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserUsecaseMockRecorder) ChangePassword(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserUsecase)(nil).ChangePassword), arg0, arg1, arg2, arg3)
+}
+
 // Login mocks base method.
-func (m *MockUserUsecase) Login(arg0 context.Context, arg1, arg2 string) (*user.User, error) {
+func (m *MockUserUsecase) Login(arg0 context.Context, arg1, arg2, arg3 string) (*user.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Login", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*user.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockUserUsecaseMockRecorder) Login(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockUserUsecaseMockRecorder) Login(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUsecase)(nil).Login), arg0, arg1, arg2, arg3)
+}
+
+// LoginSecondFactor mocks base method.
+func (m *MockUserUsecase) LoginSecondFactor(arg0 context.Context, arg1, arg2 string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginSecondFactor", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginSecondFactor indicates an expected call of LoginSecondFactor.
+func (mr *MockUserUsecaseMockRecorder) LoginSecondFactor(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUsecase)(nil).Login), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginSecondFactor", reflect.TypeOf((*MockUserUsecase)(nil).LoginSecondFactor), arg0, arg1, arg2)
 }
 
 // Register mocks base method.
-func (m *MockUserUsecase) Register(arg0 context.Context, arg1, arg2 string) (*user.User, error) {
+func (m *MockUserUsecase) Register(arg0 context.Context, arg1, arg2, arg3, arg4 string) (*user.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Register", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(*user.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockUserUsecaseMockRecorder) Register(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockUserUsecaseMockRecorder) Register(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUsecase)(nil).Register), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUsecase)(nil).Register), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RequestReset mocks base method.
+func (m *MockUserUsecase) RequestReset(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestReset indicates an expected call of RequestReset.
+func (mr *MockUserUsecaseMockRecorder) RequestReset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReset", reflect.TypeOf((*MockUserUsecase)(nil).RequestReset), arg0, arg1)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserUsecase) ResetPassword(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserUsecaseMockRecorder) ResetPassword(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserUsecase)(nil).ResetPassword), arg0, arg1, arg2)
 }
 
 // MockSessionManager is a mock of SessionManager interface.
@@ -92,17 +153,184 @@ func (m *MockSessionManager) EXPECT() *MockSessionManagerMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockSessionManager) Create(arg0 string) (string, error) {
+// Issue mocks base method.
+func (m *MockSessionManager) Issue(arg0 context.Context, arg1 string) (*token.Pair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", arg0, arg1)
+	ret0, _ := ret[0].(*token.Pair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MockSessionManagerMockRecorder) Issue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockSessionManager)(nil).Issue), arg0, arg1)
+}
+
+// MockProfileUsecase is a mock of ProfileUsecase interface.
+type MockProfileUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileUsecaseMockRecorder
+}
+
+// MockProfileUsecaseMockRecorder is the mock recorder for MockProfileUsecase.
+type MockProfileUsecaseMockRecorder struct {
+	mock *MockProfileUsecase
+}
+
+// NewMockProfileUsecase creates a new mock instance.
+func NewMockProfileUsecase(ctrl *gomock.Controller) *MockProfileUsecase {
+	mock := &MockProfileUsecase{ctrl: ctrl}
+	mock.recorder = &MockProfileUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileUsecase) EXPECT() *MockProfileUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetProfile mocks base method.
+func (m *MockProfileUsecase) GetProfile(arg0 context.Context, arg1 string) (*user.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", arg0, arg1)
+	ret0, _ := ret[0].(*user.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockProfileUsecaseMockRecorder) GetProfile(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockProfileUsecase)(nil).GetProfile), arg0, arg1)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockProfileUsecase) UpdateProfile(arg0 context.Context, arg1 string, arg2 user.ProfileUpdate) (*user.Profile, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "UpdateProfile", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*user.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockProfileUsecaseMockRecorder) UpdateProfile(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockProfileUsecase)(nil).UpdateProfile), arg0, arg1, arg2)
+}
+
+// MockAuth is a mock of Auth interface.
+type MockAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthMockRecorder
+}
+
+// MockAuthMockRecorder is the mock recorder for MockAuth.
+type MockAuthMockRecorder struct {
+	mock *MockAuth
+}
+
+// NewMockAuth creates a new mock instance.
+func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
+	mock := &MockAuth{ctrl: ctrl}
+	mock.recorder = &MockAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+	return m.recorder
+}
+
+// GetUserID mocks base method.
+func (m *MockAuth) GetUserID(arg0 echo.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserID", arg0)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockSessionManagerMockRecorder) Create(arg0 any) *gomock.Call {
+// GetUserID indicates an expected call of GetUserID.
+func (mr *MockAuthMockRecorder) GetUserID(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserID", reflect.TypeOf((*MockAuth)(nil).GetUserID), arg0)
+}
+
+// MockTiers is a mock of Tiers interface.
+type MockTiers struct {
+	ctrl     *gomock.Controller
+	recorder *MockTiersMockRecorder
+}
+
+// MockTiersMockRecorder is the mock recorder for MockTiers.
+type MockTiersMockRecorder struct {
+	mock *MockTiers
+}
+
+// NewMockTiers creates a new mock instance.
+func NewMockTiers(ctrl *gomock.Controller) *MockTiers {
+	mock := &MockTiers{ctrl: ctrl}
+	mock.recorder = &MockTiersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTiers) EXPECT() *MockTiersMockRecorder {
+	return m.recorder
+}
+
+// GetStatus mocks base method.
+func (m *MockTiers) GetStatus(arg0 context.Context, arg1 string) (*tier.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", arg0, arg1)
+	ret0, _ := ret[0].(*tier.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockTiersMockRecorder) GetStatus(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockTiers)(nil).GetStatus), arg0, arg1)
+}
+
+// MockBalances is a mock of Balances interface.
+type MockBalances struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalancesMockRecorder
+}
+
+// MockBalancesMockRecorder is the mock recorder for MockBalances.
+type MockBalancesMockRecorder struct {
+	mock *MockBalances
+}
+
+// NewMockBalances creates a new mock instance.
+func NewMockBalances(ctrl *gomock.Controller) *MockBalances {
+	mock := &MockBalances{ctrl: ctrl}
+	mock.recorder = &MockBalancesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalances) EXPECT() *MockBalancesMockRecorder {
+	return m.recorder
+}
+
+// GetBalanceByUser mocks base method.
+func (m *MockBalances) GetBalanceByUser(arg0 context.Context, arg1 string) (*balance.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceByUser", arg0, arg1)
+	ret0, _ := ret[0].(*balance.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceByUser indicates an expected call of GetBalanceByUser.
+func (mr *MockBalancesMockRecorder) GetBalanceByUser(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionManager)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceByUser", reflect.TypeOf((*MockBalances)(nil).GetBalanceByUser), arg0, arg1)
 }
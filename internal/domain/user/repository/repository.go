@@ -4,13 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/benderr/gophermart/internal/domain/user"
 	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+const profileColumns = `id, login, role, created_at, display_name, email, phone, notify_email, notify_sms, notify_promo`
+
 type userRepository struct {
 	db  *sql.DB
 	log logger.Logger
@@ -22,9 +26,9 @@ func New(db *sql.DB, log logger.Logger) *userRepository {
 
 func (u *userRepository) GetUserByLogin(ctx context.Context, login string) (*user.User, error) {
 
-	row := u.db.QueryRowContext(ctx, "SELECT id, login, passhash, created_at from users WHERE login = $1", login)
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, "SELECT id, login, passhash, role, created_at from users WHERE login = $1", login)
 	var usr user.User
-	err := row.Scan(&usr.ID, &usr.Login, &usr.Password, &usr.CreatedAt)
+	err := row.Scan(&usr.ID, &usr.Login, &usr.Password, &usr.Role, &usr.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, user.ErrNotFound
@@ -36,8 +40,11 @@ func (u *userRepository) GetUserByLogin(ctx context.Context, login string) (*use
 	return &usr, nil
 }
 
-func (u *userRepository) AddUser(ctx context.Context, login, passhash string) (*user.User, error) {
-	_, err := u.db.ExecContext(ctx, `INSERT INTO users (login, passhash) VALUES($1, $2)`, login, passhash)
+func (u *userRepository) AddUser(ctx context.Context, login, passhash, ip string) (*user.User, error) {
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, `INSERT INTO users (login, passhash, registered_ip) VALUES($1, $2, NULLIF($3, ''))
+	RETURNING id, login, passhash, role, created_at`, login, passhash, ip)
+	var usr user.User
+	err := row.Scan(&usr.ID, &usr.Login, &usr.Password, &usr.Role, &usr.CreatedAt)
 	if err != nil {
 		var perr *pgconn.PgError
 		if errors.As(err, &perr) && perr.Code == pgerrcode.UniqueViolation {
@@ -47,5 +54,106 @@ func (u *userRepository) AddUser(ctx context.Context, login, passhash string) (*
 		return nil, err
 	}
 
-	return u.GetUserByLogin(ctx, login)
+	return &usr, nil
+}
+
+func (u *userRepository) GetUserByID(ctx context.Context, id string) (*user.User, error) {
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, "SELECT id, login, passhash, role, created_at from users WHERE id = $1", id)
+	var usr user.User
+	err := row.Scan(&usr.ID, &usr.Login, &usr.Password, &usr.Role, &usr.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &usr, nil
+}
+
+func (u *userRepository) UpdatePassword(ctx context.Context, id string, passhash string) error {
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE users SET passhash=$2 WHERE id=$1`, id, passhash)
+	return err
+}
+
+func (u *userRepository) CreateReset(ctx context.Context, userid string, hash string, ttl time.Duration) error {
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `INSERT INTO password_resets (user_id, token_hash, expires_at)
+	VALUES($1, $2, NOW() + $3 * interval '1 second')`, userid, hash, ttl.Seconds())
+	u.log.Infoln("[CREATE PASSWORD RESET]", userid)
+	return err
+}
+
+// Запрос на сброс по хешу токена с блокировкой строки
+func (u *userRepository) GetReset(ctx context.Context, hash string) (*user.Reset, error) {
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, `SELECT id, user_id, used_at IS NOT NULL, expires_at <= NOW()
+	FROM password_resets WHERE token_hash=$1 FOR UPDATE`, hash)
+
+	var r user.Reset
+	err := row.Scan(&r.ID, &r.UserID, &r.Used, &r.Expired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, user.ErrInvalidResetToken
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Помечает использованными все неиспользованные запросы на сброс пользователя
+func (u *userRepository) UseResets(ctx context.Context, userid string) error {
+	_, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE password_resets SET used_at=NOW() WHERE user_id=$1 AND used_at IS NULL`, userid)
+	return err
+}
+
+func (u *userRepository) SetRole(ctx context.Context, id string, role user.Role) error {
+	res, err := transactor.FromContext(ctx, u.db).ExecContext(ctx, `UPDATE users SET role=$2 WHERE id=$1`, id, role)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return user.ErrNotFound
+	}
+	return nil
+}
+
+func (u *userRepository) GetProfile(ctx context.Context, id string) (*user.Profile, error) {
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, `SELECT `+profileColumns+` FROM users WHERE id=$1`, id)
+	return scanProfile(row)
+}
+
+// Меняет только переданные поля
+func (u *userRepository) UpdateProfile(ctx context.Context, id string, p user.ProfileUpdate) (*user.Profile, error) {
+	row := transactor.FromContext(ctx, u.db).QueryRowContext(ctx, `UPDATE users SET
+		display_name=COALESCE($2, display_name),
+		email=COALESCE($3, email),
+		phone=COALESCE($4, phone),
+		notify_email=COALESCE($5, notify_email),
+		notify_sms=COALESCE($6, notify_sms),
+		notify_promo=COALESCE($7, notify_promo)
+	WHERE id=$1
+	RETURNING `+profileColumns, id, p.DisplayName, p.Email, p.Phone, p.NotifyEmail, p.NotifySMS, p.NotifyPromo)
+	u.log.Infoln("[UPDATE PROFILE]", id)
+	return scanProfile(row)
+}
+
+func scanProfile(row *sql.Row) (*user.Profile, error) {
+	var p user.Profile
+	err := row.Scan(&p.ID, &p.Login, &p.Role, &p.CreatedAt, &p.DisplayName, &p.Email, &p.Phone,
+		&p.Notifications.Email, &p.Notifications.SMS, &p.Notifications.Promo)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &p, nil
 }
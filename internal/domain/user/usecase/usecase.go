@@ -2,48 +2,359 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/benderr/gophermart/internal/domain/token"
 	"github.com/benderr/gophermart/internal/domain/user"
 	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/notifier"
+	"github.com/benderr/gophermart/internal/transactor"
 )
 
 type userRepo interface {
 	GetUserByLogin(ctx context.Context, login string) (*user.User, error)
-	AddUser(ctx context.Context, login, passhash string) (*user.User, error)
+	GetUserByID(ctx context.Context, id string) (*user.User, error)
+	AddUser(ctx context.Context, login, passhash, ip string) (*user.User, error)
+	UpdatePassword(ctx context.Context, id string, passhash string) error
+	CreateReset(ctx context.Context, userid string, hash string, ttl time.Duration) error
+	GetReset(ctx context.Context, hash string) (*user.Reset, error)
+	UseResets(ctx context.Context, userid string) error
+	SetRole(ctx context.Context, id string, role user.Role) error
+	GetProfile(ctx context.Context, id string) (*user.Profile, error)
+	UpdateProfile(ctx context.Context, id string, p user.ProfileUpdate) (*user.Profile, error)
+}
+
+type referrals interface {
+	Register(ctx context.Context, userid string, code string, ip string) error
+}
+
+type welcome interface {
+	Grant(ctx context.Context, userid string) (float64, error)
+}
+
+type lockout interface {
+	Reserve(ctx context.Context, login, ip string) error
+	Reset(ctx context.Context, login, ip string) error
+	Release(ctx context.Context, login, ip string) error
+}
+
+type twoFactor interface {
+	IsEnabled(ctx context.Context, userid string) (bool, error)
+	Verify(ctx context.Context, userid string, code string) error
+}
+
+type sessions interface {
+	RevokeAll(ctx context.Context, userid string) error
+}
+
+type hasher interface {
+	Hash(ctx context.Context, password string) (string, error)
+	Verify(ctx context.Context, password, hash string) (bool, error)
+	NeedsRehash(hash string) bool
+}
+
+type notify interface {
+	Notify(ctx context.Context, m notifier.Message) error
+}
+
+type trans interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
 }
 
 type userUsecase struct {
-	repo   userRepo
-	logger logger.Logger
+	repo       userRepo
+	referrals  referrals
+	welcome    welcome
+	lockout    lockout
+	twoFactor  twoFactor
+	sessions   sessions
+	notifier   notify
+	hasher     hasher
+	transactor trans
+	resetTTL   time.Duration
+	logger     logger.Logger
 }
 
-func New(repo userRepo, logger logger.Logger) *userUsecase {
-	return &userUsecase{repo: repo, logger: logger}
+func New(repo userRepo, ref referrals, w welcome, lk lockout, tf twoFactor, s sessions, n notify, h hasher, t trans, resetTTL time.Duration, logger logger.Logger) *userUsecase {
+	return &userUsecase{
+		repo:       repo,
+		referrals:  ref,
+		welcome:    w,
+		lockout:    lk,
+		twoFactor:  tf,
+		sessions:   s,
+		notifier:   n,
+		hasher:     h,
+		transactor: t,
+		resetTTL:   resetTTL,
+		logger:     logger,
+	}
 }
 
-func (u *userUsecase) Login(ctx context.Context, login, password string) (*user.User, error) {
+// Неудачные попытки учитываются по логину и по IP клиента. Попытка засчитывается как неудачная
+// до проверки пароля и снимается при успехе. Пока действует задержка, попытка отклоняется
+// без проверки пароля
+func (u *userUsecase) Login(ctx context.Context, login, password, ip string) (*user.User, error) {
+	if err := u.lockout.Reserve(ctx, login, ip); err != nil {
+		return nil, err
+	}
+
 	usr, err := u.repo.GetUserByLogin(ctx, login)
 	if err != nil {
+		if !errors.Is(err, user.ErrNotFound) {
+			u.release(ctx, login, ip)
+		}
 		return nil, err
 	}
 
-	if usr == nil {
-		return nil, user.ErrNotFound
+	ok, err := u.hasher.Verify(ctx, password, usr.Password)
+	if err != nil {
+		u.release(ctx, login, ip)
+		return nil, err
 	}
 
-	if !CheckPasswordHash(password, usr.Password) {
+	if !ok {
 		return nil, user.ErrBadPass
 	}
 
+	if err := u.lockout.Reset(ctx, login, ip); err != nil {
+		u.logger.Errorln("reset login attempts error", err)
+	}
+
+	u.rehash(ctx, usr, password)
+
+	usr.TwoFactor, err = u.twoFactor.IsEnabled(ctx, usr.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return usr, nil
 }
 
-func (u *userUsecase) Register(ctx context.Context, login, password string) (*user.User, error) {
-	passhash, err := HashPassword(password)
+// Второй шаг входа для пользователей с 2FA: проверка кода из приложения или резервного кода
+func (u *userUsecase) LoginSecondFactor(ctx context.Context, userid, code string) (*user.User, error) {
+	if err := u.twoFactor.Verify(ctx, userid, code); err != nil {
+		return nil, err
+	}
+
+	return u.repo.GetUserByID(ctx, userid)
+}
+
+// Хеш старым алгоритмом или с устаревшими параметрами пересчитывается после успешного входа,
+// пока известен пароль. Ошибка не мешает входу, пересчет повторится при следующем
+func (u *userUsecase) rehash(ctx context.Context, usr *user.User, password string) {
+	if !u.hasher.NeedsRehash(usr.Password) {
+		return
+	}
+
+	passhash, err := u.hasher.Hash(ctx, password)
+	if err != nil {
+		u.logger.Errorln("rehash password error", err)
+		return
+	}
+
+	if err := u.repo.UpdatePassword(ctx, usr.ID, passhash); err != nil {
+		u.logger.Errorln("rehash password error", err)
+		return
+	}
+
+	usr.Password = passhash
+	u.logger.Infoln("[PASSWORD REHASHED]", usr.ID)
+}
+
+func (u *userUsecase) release(ctx context.Context, login, ip string) {
+	if err := u.lockout.Release(ctx, login, ip); err != nil {
+		u.logger.Errorln("release login attempt error", err)
+	}
+}
+
+// Регистрирует пользователя, выдает ему реферальный код и приветственный бонус в одной транзакции.
+// Неверный код пригласившего отменяет регистрацию
+func (u *userUsecase) Register(ctx context.Context, login, password, referralCode, ip string) (*user.User, error) {
+	passhash, err := u.hasher.Hash(ctx, password)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return u.repo.AddUser(ctx, login, passhash)
+	var created *user.User
+	err = u.transactor.Within(ctx, func(ctx context.Context) error {
+		created, err = u.repo.AddUser(ctx, login, passhash, ip)
+		if err != nil {
+			return err
+		}
+
+		err = u.referrals.Register(ctx, created.ID, referralCode, ip)
+		if err != nil {
+			return err
+		}
+
+		_, err = u.welcome.Grant(ctx, created.ID)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// Меняет пароль после проверки текущего и отзывает все сессии пользователя
+func (u *userUsecase) ChangePassword(ctx context.Context, userid, oldPassword, newPassword string) error {
+	usr, err := u.repo.GetUserByID(ctx, userid)
+	if err != nil {
+		return err
+	}
+
+	ok, err := u.hasher.Verify(ctx, oldPassword, usr.Password)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return user.ErrBadPass
+	}
+
+	if err := u.setPassword(ctx, userid, newPassword); err != nil {
+		return err
+	}
+
+	u.logger.Infoln("[PASSWORD CHANGED]", userid)
+	return nil
+}
+
+// Отправляет пользователю одноразовый токен сброса пароля.
+// Для неизвестного логина ошибку не возвращаем, чтобы нельзя было перебирать логины
+func (u *userUsecase) RequestReset(ctx context.Context, login string) error {
+	usr, err := u.repo.GetUserByLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			u.logger.Infoln("[PASSWORD RESET] unknown login", login)
+			return nil
+		}
+		return err
+	}
+
+	raw, hash, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.CreateReset(ctx, usr.ID, hash, u.resetTTL); err != nil {
+		return err
+	}
+
+	return u.notifier.Notify(ctx, notifier.Message{
+		UserID:  usr.ID,
+		Login:   usr.Login,
+		Subject: "Сброс пароля",
+		Body:    fmt.Sprintf("Токен для сброса пароля: %s. Токен действует %s", raw, u.resetTTL),
+	})
+}
+
+// Устанавливает новый пароль по токену сброса. Токен и остальные запросы на сброс гасятся
+func (u *userUsecase) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	tokenHash := token.Hash(resetToken)
+
+	//токен проверяем до хеширования, чтобы перебор токенов не занимал пул хеширования
+	if _, err := u.activeReset(ctx, tokenHash); err != nil {
+		return err
+	}
+
+	passhash, err := u.hasher.Hash(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+
+	var userid string
+	err = u.transactor.Within(ctx, func(ctx context.Context) error {
+		//повторная проверка в транзакции: токен мог быть использован, пока считался хеш
+		userid, err = u.activeReset(ctx, tokenHash)
+		if err != nil {
+			return err
+		}
+
+		return u.updatePassword(ctx, userid, passhash)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	u.logger.Infoln("[PASSWORD RESET]", userid)
+	return nil
+}
+
+// Владелец неиспользованного и непросроченного токена сброса
+func (u *userUsecase) activeReset(ctx context.Context, tokenHash string) (string, error) {
+	r, err := u.repo.GetReset(ctx, tokenHash)
+	if err != nil {
+		return "", err
+	}
+
+	if r.Used || r.Expired {
+		return "", user.ErrInvalidResetToken
+	}
+
+	return r.UserID, nil
+}
+
+func (u *userUsecase) setPassword(ctx context.Context, userid, password string) error {
+	passhash, err := u.hasher.Hash(ctx, password)
+	if err != nil {
+		return err
+	}
+
+	return u.transactor.Within(ctx, func(ctx context.Context) error {
+		return u.updatePassword(ctx, userid, passhash)
+	})
+}
+
+func (u *userUsecase) updatePassword(ctx context.Context, userid, passhash string) error {
+	if err := u.repo.UpdatePassword(ctx, userid, passhash); err != nil {
+		return err
+	}
+
+	if err := u.repo.UseResets(ctx, userid); err != nil {
+		return err
+	}
+
+	return u.sessions.RevokeAll(ctx, userid)
+}
+
+func (u *userUsecase) GetByID(ctx context.Context, id string) (*user.User, error) {
+	return u.repo.GetUserByID(ctx, id)
+}
+
+func (u *userUsecase) GetByLogin(ctx context.Context, login string) (*user.User, error) {
+	return u.repo.GetUserByLogin(ctx, login)
+}
+
+// Меняет роль и отзывает все сессии пользователя, чтобы он вошел заново с новой ролью
+func (u *userUsecase) SetRole(ctx context.Context, actor string, id string, role user.Role) error {
+	err := u.transactor.Within(ctx, func(ctx context.Context) error {
+		if err := u.repo.SetRole(ctx, id, role); err != nil {
+			return err
+		}
+
+		return u.sessions.RevokeAll(ctx, id)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	u.logger.Infoln("[SECURITY] role changed", id, role, "by", actor)
+	return nil
+}
+
+func (u *userUsecase) GetProfile(ctx context.Context, userid string) (*user.Profile, error) {
+	return u.repo.GetProfile(ctx, userid)
+}
+
+func (u *userUsecase) UpdateProfile(ctx context.Context, userid string, p user.ProfileUpdate) (*user.Profile, error) {
+	return u.repo.UpdateProfile(ctx, userid, p)
 }
@@ -5,17 +5,76 @@ import (
 	"time"
 )
 
+type Role string
+
+const (
+	RoleUser    Role = "user"
+	RoleSupport Role = "support"
+	RoleAdmin   Role = "admin"
+)
+
+func ParseRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case RoleUser, RoleSupport, RoleAdmin:
+		return r, nil
+	}
+	return "", ErrInvalidRole
+}
+
 type User struct {
 	ID        string    `json:"id"`
 	Login     string    `json:"login" validate:"required"`
-	Password  string    `json:"password" validate:"required"`
+	Password  string    `json:"-" validate:"required"`
+	Role      Role      `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
+	//для входа нужен код 2FA
+	TwoFactor bool `json:"-"`
+}
+
+// Какие уведомления пользователь хочет получать
+type Notifications struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+	Promo bool `json:"promo"`
+}
+
+// Профиль пользователя: учетные данные и поля, которые пользователь заполняет сам
+type Profile struct {
+	ID            string        `json:"id"`
+	Login         string        `json:"login"`
+	Role          Role          `json:"role"`
+	CreatedAt     time.Time     `json:"created_at"`
+	DisplayName   string        `json:"display_name"`
+	Email         string        `json:"email"`
+	Phone         string        `json:"phone"`
+	Notifications Notifications `json:"notifications"`
+}
+
+// Частичное изменение профиля, nil - поле не меняется
+type ProfileUpdate struct {
+	DisplayName *string
+	Email       *string
+	Phone       *string
+	NotifyEmail *bool
+	NotifySMS   *bool
+	NotifyPromo *bool
+}
+
+// Запрос на сброс пароля. Токен одноразовый и действует ограниченное время
+type Reset struct {
+	ID      string
+	UserID  string
+	Used    bool
+	Expired bool
 }
 
 var (
-	ErrBadPass    = errors.New("bad pass")
-	ErrNotFound   = errors.New("user not found")
-	ErrLoginExist = errors.New("login already exist")
+	ErrBadPass     = errors.New("bad pass")
+	ErrNotFound    = errors.New("user not found")
+	ErrLoginExist  = errors.New("login already exist")
+	ErrInvalidRole = errors.New("invalid role")
+	//токен сброса пароля не найден, использован или истек
+	ErrInvalidResetToken = errors.New("invalid reset token")
 )
 
 //Валидация
@@ -0,0 +1,174 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/voucher"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type VoucherUsecase interface {
+	CreateBatch(ctx context.Context, b *voucher.Batch, count int) (*voucher.Batch, error)
+	Redeem(ctx context.Context, userid string, code string) (*voucher.Redemption, error)
+	GetReports(ctx context.Context) ([]voucher.Report, error)
+	GetReport(ctx context.Context, id string) (*voucher.Report, error)
+}
+
+type SessionManager interface {
+	GetUserID(c echo.Context) (string, error)
+}
+
+type voucherHandler struct {
+	session SessionManager
+	logger  logger.Logger
+	VoucherUsecase
+}
+
+type BatchModel struct {
+	Name           string    `json:"name" validate:"required"`
+	Amount         float64   `json:"amount" validate:"required,gt=0"`
+	Count          int       `json:"count" validate:"required,gt=0"`
+	MaxRedemptions int       `json:"max_redemptions" validate:"gte=0"`
+	ExpiresAt      time.Time `json:"expires_at" validate:"required"`
+}
+
+type RedeemModel struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// Погашение кода пользователем, limiters ограничивают частоту попыток подбора кодов
+func NewVoucherHandlers(group *echo.Group, vu VoucherUsecase, session SessionManager, logger logger.Logger, limiters ...echo.MiddlewareFunc) {
+	h := &voucherHandler{
+		VoucherUsecase: vu,
+		session:        session,
+		logger:         logger,
+	}
+
+	g := group.Group("/api/user")
+
+	g.POST("/vouchers/redeem", h.RedeemHandler, limiters...)
+}
+
+// Регистрирует обработчики в группе /api/admin, доступ к которой уже ограничен администраторами
+func NewVoucherAdminHandlers(group *echo.Group, vu VoucherUsecase, session SessionManager, logger logger.Logger) {
+	h := &voucherHandler{
+		VoucherUsecase: vu,
+		session:        session,
+		logger:         logger,
+	}
+
+	group.POST("/vouchers", h.CreateBatchHandler)
+	group.GET("/vouchers", h.GetReportsHandler)
+	group.GET("/vouchers/:id", h.GetReportHandler)
+}
+
+func (h *voucherHandler) RedeemHandler(c echo.Context) error {
+	var m RedeemModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	userid, err := h.session.GetUserID(c)
+	if err != nil {
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	red, err := h.Redeem(c.Request().Context(), userid, m.Code)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, voucher.ErrNotFound):
+			return c.JSON(http.StatusNotFound, httputils.Error(err.Error()))
+		case errors.Is(err, voucher.ErrExpired):
+			return c.JSON(http.StatusGone, httputils.Error(err.Error()))
+		case errors.Is(err, voucher.ErrExhausted),
+			errors.Is(err, voucher.ErrAlreadyRedeemed):
+			return c.JSON(http.StatusConflict, httputils.Error(err.Error()))
+		}
+
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, red)
+}
+
+func (h *voucherHandler) CreateBatchHandler(c echo.Context) error {
+	var m BatchModel
+
+	if err := c.Bind(&m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	if err := c.Validate(m); err != nil {
+		return c.JSON(http.StatusBadRequest, httputils.Error("invalid request payload"))
+	}
+
+	actor, err := h.session.GetUserID(c)
+	if err != nil {
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if m.MaxRedemptions == 0 {
+		m.MaxRedemptions = 1
+	}
+
+	created, err := h.CreateBatch(c.Request().Context(), &voucher.Batch{
+		Name:           m.Name,
+		Amount:         m.Amount,
+		MaxRedemptions: m.MaxRedemptions,
+		ExpiresAt:      m.ExpiresAt,
+		CreatedBy:      actor,
+	}, m.Count)
+
+	if err != nil {
+		if errors.Is(err, voucher.ErrInvalidBatch) {
+			return c.JSON(http.StatusUnprocessableEntity, httputils.Error(err.Error()))
+		}
+
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *voucherHandler) GetReportsHandler(c echo.Context) error {
+	list, err := h.GetReports(c.Request().Context())
+	if err != nil {
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	if len(list) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *voucherHandler) GetReportHandler(c echo.Context) error {
+	rep, err := h.GetReport(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, voucher.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, httputils.Error(err.Error()))
+		}
+
+		h.logger.Errorln(err)
+		return c.JSON(http.StatusInternalServerError, httputils.Error("internal server error"))
+	}
+
+	return c.JSON(http.StatusOK, rep)
+}
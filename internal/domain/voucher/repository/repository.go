@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/benderr/gophermart/internal/domain/voucher"
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+const reportQuery = `SELECT b.id, b.name, b.amount, b.max_redemptions, b.expires_at, b.created_by, b.created_at,
+	COUNT(v.code), COUNT(v.code) FILTER (WHERE v.redeemed > 0), COALESCE(SUM(v.redeemed), 0), COALESCE(SUM(v.redeemed), 0) * b.amount
+	FROM voucher_batches b
+	LEFT JOIN vouchers v ON v.batch_id = b.id`
+
+type voucherRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *voucherRepository {
+	return &voucherRepository{db: db, log: log}
+}
+
+func (r *voucherRepository) CreateBatch(ctx context.Context, b *voucher.Batch) (*voucher.Batch, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `INSERT INTO voucher_batches (name, amount, max_redemptions, expires_at, created_by)
+	VALUES($1, $2, $3, $4::timestamptz, $5)
+	RETURNING id, name, amount, max_redemptions, expires_at, created_by, created_at`,
+		b.Name, b.Amount, b.MaxRedemptions, b.ExpiresAt, b.CreatedBy)
+
+	var created voucher.Batch
+	err := row.Scan(&created.ID, &created.Name, &created.Amount, &created.MaxRedemptions, &created.ExpiresAt, &created.CreatedBy, &created.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	r.log.Infow("[CREATE VOUCHER BATCH]", "batch", created)
+	return &created, nil
+}
+
+// Сохраняет коды партии, уже существующие коды пропускает. Возвращает сохраненные коды
+func (r *voucherRepository) AddCodes(ctx context.Context, batchID string, codes []string) ([]string, error) {
+	rows, err := transactor.FromContext(ctx, r.db).QueryContext(ctx, `INSERT INTO vouchers (code, batch_id)
+	SELECT unnest($1::text[]), $2
+	ON CONFLICT (code) DO NOTHING
+	RETURNING code`, codes, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	added := make([]string, 0, len(codes))
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		added = append(added, code)
+	}
+
+	return added, rows.Err()
+}
+
+// Код с параметрами партии и блокировкой строки кода
+func (r *voucherRepository) GetForUpdate(ctx context.Context, code string) (*voucher.Voucher, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `SELECT v.code, v.batch_id, b.amount, b.max_redemptions, v.redeemed, b.expires_at <= NOW()
+	FROM vouchers v
+	JOIN voucher_batches b ON b.id = v.batch_id
+	WHERE v.code=$1
+	FOR UPDATE OF v`, code)
+
+	var v voucher.Voucher
+	err := row.Scan(&v.Code, &v.BatchID, &v.Amount, &v.MaxRedemptions, &v.Redeemed, &v.Expired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, voucher.ErrNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Фиксирует погашение кода пользователем, повторное погашение тем же пользователем - ErrAlreadyRedeemed
+func (r *voucherRepository) Redeem(ctx context.Context, code string, userid string, amount float64) (*voucher.Redemption, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, `INSERT INTO voucher_redemptions (code, user_id, amount)
+	VALUES($1, $2, $3)
+	ON CONFLICT (code, user_id) DO NOTHING
+	RETURNING code, amount, created_at`, code, userid, amount)
+
+	var red voucher.Redemption
+	err := row.Scan(&red.Code, &red.Amount, &red.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, voucher.ErrAlreadyRedeemed
+		}
+		return nil, err
+	}
+
+	_, err = transactor.FromContext(ctx, r.db).ExecContext(ctx, `UPDATE vouchers SET redeemed=redeemed + 1 WHERE code=$1`, code)
+	if err != nil {
+		return nil, err
+	}
+
+	r.log.Infoln("[REDEEM VOUCHER]", code, userid, amount)
+	return &red, nil
+}
+
+func (r *voucherRepository) GetReports(ctx context.Context) ([]voucher.Report, error) {
+	rows, err := transactor.FromContext(ctx, r.db).QueryContext(ctx, reportQuery+` GROUP BY b.id ORDER BY b.created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	list := make([]voucher.Report, 0)
+	for rows.Next() {
+		rep, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *rep)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *voucherRepository) GetReport(ctx context.Context, id string) (*voucher.Report, error) {
+	row := transactor.FromContext(ctx, r.db).QueryRowContext(ctx, reportQuery+` WHERE b.id=$1 GROUP BY b.id`, id)
+	rep, err := scanReport(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, voucher.ErrNotFound
+		}
+		return nil, err
+	}
+	return rep, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReport(row scanner) (*voucher.Report, error) {
+	var rep voucher.Report
+	err := row.Scan(&rep.ID, &rep.Name, &rep.Amount, &rep.MaxRedemptions, &rep.ExpiresAt, &rep.CreatedBy, &rep.CreatedAt,
+		&rep.Total, &rep.RedeemedCodes, &rep.Redemptions, &rep.Credited)
+	if err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/voucher"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+// Количество попыток догенерировать коды взамен совпавших с существующими
+const codeAttempts = 3
+
+var errCodesExhausted = errors.New("failed to generate unique voucher codes")
+
+type voucherUsecase struct {
+	voucherRepo VoucherRepo
+	balanceRepo BalanceRepo
+	points      Points
+	transactor  Transactor
+	logger      logger.Logger
+}
+
+func New(vr VoucherRepo, br BalanceRepo, pts Points, t Transactor, l logger.Logger) *voucherUsecase {
+	return &voucherUsecase{
+		voucherRepo: vr,
+		balanceRepo: br,
+		points:      pts,
+		transactor:  t,
+		logger:      l}
+}
+
+// Создает партию из count кодов. Партия и коды сохраняются в одной транзакции
+func (v *voucherUsecase) CreateBatch(ctx context.Context, b *voucher.Batch, count int) (*voucher.Batch, error) {
+	if err := b.Validate(count, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var created *voucher.Batch
+	err := v.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = v.voucherRepo.CreateBatch(ctx, b)
+		if err != nil {
+			return err
+		}
+
+		created.Codes = make([]string, 0, count)
+		for attempt := 0; attempt < codeAttempts && len(created.Codes) < count; attempt++ {
+			codes, err := generateCodes(count - len(created.Codes))
+			if err != nil {
+				return err
+			}
+
+			added, err := v.voucherRepo.AddCodes(ctx, created.ID, codes)
+			if err != nil {
+				return err
+			}
+			created.Codes = append(created.Codes, added...)
+		}
+
+		if len(created.Codes) < count {
+			return errCodesExhausted
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Погашает код и начисляет его номинал пользователю в одной транзакции
+func (v *voucherUsecase) Redeem(ctx context.Context, userid string, code string) (*voucher.Redemption, error) {
+	code = voucher.Normalize(code)
+
+	var red *voucher.Redemption
+	err := v.transactor.Within(ctx, func(ctx context.Context) error {
+		vc, err := v.voucherRepo.GetForUpdate(ctx, code)
+		if err != nil {
+			return err
+		}
+
+		if err := vc.Check(); err != nil {
+			return err
+		}
+
+		red, err = v.voucherRepo.Redeem(ctx, vc.Code, userid, vc.Amount)
+		if err != nil {
+			return err
+		}
+
+		net, err := v.balanceRepo.Add(ctx, userid, &vc.Amount)
+		if err != nil {
+			return err
+		}
+
+		return v.points.Credit(ctx, userid, points.SourceVoucher, "", vc.Amount, net)
+	})
+
+	if err != nil {
+		if !errors.Is(err, voucher.ErrNotFound) {
+			v.logger.Infow("[REDEEM VOUCHER FAILED]", "user", userid, "code", code, "error", err)
+		}
+		return nil, err
+	}
+	return red, nil
+}
+
+func (v *voucherUsecase) GetReports(ctx context.Context) ([]voucher.Report, error) {
+	return v.voucherRepo.GetReports(ctx)
+}
+
+func (v *voucherUsecase) GetReport(ctx context.Context, id string) (*voucher.Report, error) {
+	return v.voucherRepo.GetReport(ctx, id)
+}
+
+func generateCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		code, err := voucher.GenerateCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
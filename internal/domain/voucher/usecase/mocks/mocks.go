@@ -0,0 +1,206 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/benderr/gophermart/internal/domain/voucher/usecase (interfaces: VoucherRepo,BalanceRepo,Points)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/domain/voucher/usecase/mocks/mocks.go -package=mocks github.com/benderr/gophermart/internal/domain/voucher/usecase VoucherRepo,BalanceRepo,Points
+//
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	points "github.com/benderr/gophermart/internal/domain/points"
+	voucher "github.com/benderr/gophermart/internal/domain/voucher"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVoucherRepo is a mock of VoucherRepo interface.
+type MockVoucherRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockVoucherRepoMockRecorder
+}
+
+// MockVoucherRepoMockRecorder is the mock recorder for MockVoucherRepo.
+type MockVoucherRepoMockRecorder struct {
+	mock *MockVoucherRepo
+}
+
+// NewMockVoucherRepo creates a new mock instance.
+func NewMockVoucherRepo(ctrl *gomock.Controller) *MockVoucherRepo {
+	mock := &MockVoucherRepo{ctrl: ctrl}
+	mock.recorder = &MockVoucherRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVoucherRepo) EXPECT() *MockVoucherRepoMockRecorder {
+	return m.recorder
+}
+
+// AddCodes mocks base method.
+func (m *MockVoucherRepo) AddCodes(arg0 context.Context, arg1 string, arg2 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCodes", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCodes indicates an expected call of AddCodes.
+func (mr *MockVoucherRepoMockRecorder) AddCodes(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCodes", reflect.TypeOf((*MockVoucherRepo)(nil).AddCodes), arg0, arg1, arg2)
+}
+
+// CreateBatch mocks base method.
+func (m *MockVoucherRepo) CreateBatch(arg0 context.Context, arg1 *voucher.Batch) (*voucher.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", arg0, arg1)
+	ret0, _ := ret[0].(*voucher.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockVoucherRepoMockRecorder) CreateBatch(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockVoucherRepo)(nil).CreateBatch), arg0, arg1)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockVoucherRepo) GetForUpdate(arg0 context.Context, arg1 string) (*voucher.Voucher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(*voucher.Voucher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockVoucherRepoMockRecorder) GetForUpdate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockVoucherRepo)(nil).GetForUpdate), arg0, arg1)
+}
+
+// GetReport mocks base method.
+func (m *MockVoucherRepo) GetReport(arg0 context.Context, arg1 string) (*voucher.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", arg0, arg1)
+	ret0, _ := ret[0].(*voucher.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockVoucherRepoMockRecorder) GetReport(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockVoucherRepo)(nil).GetReport), arg0, arg1)
+}
+
+// GetReports mocks base method.
+func (m *MockVoucherRepo) GetReports(arg0 context.Context) ([]voucher.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReports", arg0)
+	ret0, _ := ret[0].([]voucher.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReports indicates an expected call of GetReports.
+func (mr *MockVoucherRepoMockRecorder) GetReports(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReports", reflect.TypeOf((*MockVoucherRepo)(nil).GetReports), arg0)
+}
+
+// Redeem mocks base method.
+func (m *MockVoucherRepo) Redeem(arg0 context.Context, arg1, arg2 string, arg3 float64) (*voucher.Redemption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*voucher.Redemption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockVoucherRepoMockRecorder) Redeem(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockVoucherRepo)(nil).Redeem), arg0, arg1, arg2, arg3)
+}
+
+// MockBalanceRepo is a mock of BalanceRepo interface.
+type MockBalanceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceRepoMockRecorder
+}
+
+// MockBalanceRepoMockRecorder is the mock recorder for MockBalanceRepo.
+type MockBalanceRepoMockRecorder struct {
+	mock *MockBalanceRepo
+}
+
+// NewMockBalanceRepo creates a new mock instance.
+func NewMockBalanceRepo(ctrl *gomock.Controller) *MockBalanceRepo {
+	mock := &MockBalanceRepo{ctrl: ctrl}
+	mock.recorder = &MockBalanceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceRepo) EXPECT() *MockBalanceRepoMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockBalanceRepo) Add(arg0 context.Context, arg1 string, arg2 *float64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockBalanceRepoMockRecorder) Add(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBalanceRepo)(nil).Add), arg0, arg1, arg2)
+}
+
+// MockPoints is a mock of Points interface.
+type MockPoints struct {
+	ctrl     *gomock.Controller
+	recorder *MockPointsMockRecorder
+}
+
+// MockPointsMockRecorder is the mock recorder for MockPoints.
+type MockPointsMockRecorder struct {
+	mock *MockPoints
+}
+
+// NewMockPoints creates a new mock instance.
+func NewMockPoints(ctrl *gomock.Controller) *MockPoints {
+	mock := &MockPoints{ctrl: ctrl}
+	mock.recorder = &MockPointsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPoints) EXPECT() *MockPointsMockRecorder {
+	return m.recorder
+}
+
+// Credit mocks base method.
+func (m *MockPoints) Credit(arg0 context.Context, arg1 string, arg2 points.Source, arg3 string, arg4, arg5 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credit", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockPointsMockRecorder) Credit(arg0, arg1, arg2, arg3, arg4, arg5 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockPoints)(nil).Credit), arg0, arg1, arg2, arg3, arg4, arg5)
+}
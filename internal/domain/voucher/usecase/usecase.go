@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/voucher"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type VoucherRepo interface {
+	CreateBatch(ctx context.Context, b *voucher.Batch) (*voucher.Batch, error)
+	AddCodes(ctx context.Context, batchID string, codes []string) ([]string, error)
+	GetForUpdate(ctx context.Context, code string) (*voucher.Voucher, error)
+	Redeem(ctx context.Context, code string, userid string, amount float64) (*voucher.Redemption, error)
+	GetReports(ctx context.Context) ([]voucher.Report, error)
+	GetReport(ctx context.Context, id string) (*voucher.Report, error)
+}
+
+type BalanceRepo interface {
+	//возвращает сумму, зачисленную на баланс после погашения долга
+	Add(ctx context.Context, userid string, balance *float64) (float64, error)
+}
+
+type Points interface {
+	Credit(ctx context.Context, userid string, source points.Source, order string, credited float64, net float64) error
+}
+
+type Transactor interface {
+	Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error
+}
@@ -0,0 +1,75 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/voucher"
+	"github.com/benderr/gophermart/internal/domain/voucher/usecase"
+	"github.com/benderr/gophermart/internal/domain/voucher/usecase/mocks"
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	mocktransactor "github.com/benderr/gophermart/internal/transactor/mock_transactor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRedeem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockVoucherRepo := mocks.NewMockVoucherRepo(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepo(ctrl)
+	mockPoints := mocks.NewMockPoints(ctrl)
+	voucherUsecase := usecase.New(mockVoucherRepo, mockBalanceRepo, mockPoints, mocktransactor.New(), mocklogger.New())
+
+	const code = "ABCD-EFGH-JKLM"
+
+	t.Run("Redeem success", func(t *testing.T) {
+		vc := &voucher.Voucher{Code: code, BatchID: "batch", Amount: 100, MaxRedemptions: 2, Redeemed: 1}
+		red := &voucher.Redemption{Code: code, Amount: 100}
+
+		mockVoucherRepo.EXPECT().GetForUpdate(gomock.Any(), code).Return(vc, nil)
+		mockVoucherRepo.EXPECT().Redeem(gomock.Any(), code, "user", 100.0).Return(red, nil)
+		//часть номинала ушла на погашение долга, партия создается только на остаток
+		mockBalanceRepo.EXPECT().Add(gomock.Any(), "user", gomock.Any()).Return(70.0, nil)
+		mockPoints.EXPECT().Credit(gomock.Any(), "user", points.SourceVoucher, "", 100.0, 70.0).Return(nil)
+
+		result, err := voucherUsecase.Redeem(context.Background(), "user", "abcd efgh jklm")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, red, result)
+		}
+	})
+
+	t.Run("Redeem exhausted", func(t *testing.T) {
+		vc := &voucher.Voucher{Code: code, BatchID: "batch", Amount: 100, MaxRedemptions: 2, Redeemed: 2}
+
+		mockVoucherRepo.EXPECT().GetForUpdate(gomock.Any(), code).Return(vc, nil)
+
+		_, err := voucherUsecase.Redeem(context.Background(), "user", code)
+
+		assert.ErrorIs(t, err, voucher.ErrExhausted)
+	})
+
+	t.Run("Redeem expired", func(t *testing.T) {
+		vc := &voucher.Voucher{Code: code, BatchID: "batch", Amount: 100, MaxRedemptions: 2, Expired: true}
+
+		mockVoucherRepo.EXPECT().GetForUpdate(gomock.Any(), code).Return(vc, nil)
+
+		_, err := voucherUsecase.Redeem(context.Background(), "user", code)
+
+		assert.ErrorIs(t, err, voucher.ErrExpired)
+	})
+
+	t.Run("Redeem already redeemed by user", func(t *testing.T) {
+		vc := &voucher.Voucher{Code: code, BatchID: "batch", Amount: 100, MaxRedemptions: 2, Redeemed: 1}
+
+		mockVoucherRepo.EXPECT().GetForUpdate(gomock.Any(), code).Return(vc, nil)
+		mockVoucherRepo.EXPECT().Redeem(gomock.Any(), code, "user", 100.0).Return(nil, voucher.ErrAlreadyRedeemed)
+
+		_, err := voucherUsecase.Redeem(context.Background(), "user", code)
+
+		assert.ErrorIs(t, err, voucher.ErrAlreadyRedeemed)
+	})
+}
@@ -0,0 +1,111 @@
+package voucher
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Партия кодов одного номинала. MaxRedemptions - сколько раз можно погасить каждый код,
+// один пользователь погашает код не больше одного раза
+type Batch struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Amount         float64   `json:"amount"`
+	MaxRedemptions int       `json:"max_redemptions"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedBy      string    `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	Codes          []string  `json:"codes,omitempty"`
+}
+
+// Отчет о погашении кодов партии
+type Report struct {
+	Batch
+	Total         int     `json:"total"`
+	RedeemedCodes int     `json:"redeemed_codes"`
+	Redemptions   int     `json:"redemptions"`
+	Credited      float64 `json:"credited"`
+}
+
+// Код с параметрами партии
+type Voucher struct {
+	Code           string
+	BatchID        string
+	Amount         float64
+	MaxRedemptions int
+	Redeemed       int
+	Expired        bool
+}
+
+type Redemption struct {
+	Code      string    `json:"code"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const MaxBatchSize = 10000
+
+var (
+	ErrInvalidBatch    = errors.New("invalid voucher batch")
+	ErrNotFound        = errors.New("voucher not found")
+	ErrExpired         = errors.New("voucher expired")
+	ErrExhausted       = errors.New("voucher already used")
+	ErrAlreadyRedeemed = errors.New("voucher already redeemed by user")
+)
+
+func (b *Batch) Validate(count int, now time.Time) error {
+	if b.Amount <= 0 || b.MaxRedemptions <= 0 || count <= 0 || count > MaxBatchSize || !b.ExpiresAt.After(now) {
+		return ErrInvalidBatch
+	}
+	return nil
+}
+
+func (v *Voucher) Check() error {
+	if v.Expired {
+		return ErrExpired
+	}
+	if v.Redeemed >= v.MaxRedemptions {
+		return ErrExhausted
+	}
+	return nil
+}
+
+const (
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeGroups   = 3
+	groupLength  = 4
+)
+
+// Случайный код вида XXXX-XXXX-XXXX
+func GenerateCode() (string, error) {
+	buf := make([]byte, codeGroups*groupLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i, b := range buf {
+		if i > 0 && i%groupLength == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(codeAlphabet[int(b)%len(codeAlphabet)])
+	}
+	return sb.String(), nil
+}
+
+// Приводит введенный пользователем код к виду XXXX-XXXX-XXXX
+func Normalize(code string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r == '-' || r == ' ' {
+			continue
+		}
+		if sb.Len() > 0 && (sb.Len()+1)%(groupLength+1) == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,38 @@
+package voucher
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCode(t *testing.T) {
+	code, err := GenerateCode()
+
+	if assert.NoError(t, err) {
+		assert.Regexp(t, regexp.MustCompile(`^[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}$`), code)
+		assert.Equal(t, code, Normalize(code))
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "ABCD-EFGH-JKLM", Normalize("abcd efgh-jklm"))
+	assert.Equal(t, "ABCD-EFGH-JKLM", Normalize("ABCDEFGHJKLM"))
+}
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, (&Voucher{MaxRedemptions: 2, Redeemed: 1}).Check())
+	assert.ErrorIs(t, (&Voucher{MaxRedemptions: 1, Redeemed: 1}).Check(), ErrExhausted)
+	assert.ErrorIs(t, (&Voucher{MaxRedemptions: 1, Expired: true}).Check(), ErrExpired)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+	b := Batch{Amount: 100, MaxRedemptions: 1, ExpiresAt: now.Add(time.Hour)}
+
+	assert.NoError(t, b.Validate(10, now))
+	assert.ErrorIs(t, b.Validate(MaxBatchSize+1, now), ErrInvalidBatch)
+	assert.ErrorIs(t, b.Validate(10, now.Add(2*time.Hour)), ErrInvalidBatch)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
+)
+
+type welcomeRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func New(db *sql.DB, log logger.Logger) *welcomeRepository {
+	return &welcomeRepository{db: db, log: log}
+}
+
+// Списывает amount из общего бюджета приветственных бонусов, budget<=0 - без ограничения.
+// Блокирует строку счетчика до конца транзакции, false - бюджет исчерпан
+func (w *welcomeRepository) Reserve(ctx context.Context, amount float64, budget float64) (bool, error) {
+	row := transactor.FromContext(ctx, w.db).QueryRowContext(ctx,
+		`UPDATE welcome_budget SET spent=spent+$1 WHERE id=1 AND ($2<=0 OR spent+$1<=$2) RETURNING spent`, amount, budget)
+	var spent float64
+	err := row.Scan(&spent)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+	"github.com/benderr/gophermart/internal/domain/welcome"
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+type welcomeUsecase struct {
+	welcomeRepo WelcomeRepo
+	balanceRepo BalanceRepo
+	points      Points
+	program     welcome.Program
+	logger      logger.Logger
+}
+
+func New(wr WelcomeRepo, br BalanceRepo, pts Points, p welcome.Program, l logger.Logger) *welcomeUsecase {
+	return &welcomeUsecase{
+		welcomeRepo: wr,
+		balanceRepo: br,
+		points:      pts,
+		program:     p,
+		logger:      l}
+}
+
+// Начисляет приветственный бонус новому пользователю, если программа действует и бюджет не исчерпан.
+// Вызывается в транзакции регистрации, бюджет списывается из счетчика с блокировкой строки
+func (w *welcomeUsecase) Grant(ctx context.Context, userid string) (float64, error) {
+	if !w.program.Active(time.Now()) {
+		return 0, nil
+	}
+
+	amount := w.program.Amount
+
+	ok, err := w.welcomeRepo.Reserve(ctx, amount, w.program.Budget)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		w.logger.Infoln("[WELCOME BUDGET EXHAUSTED]", w.program.Budget)
+		return 0, nil
+	}
+
+	net, err := w.balanceRepo.Add(ctx, userid, &amount)
+	if err != nil {
+		return 0, err
+	}
+
+	err = w.points.Credit(ctx, userid, points.SourceWelcome, "", amount, net)
+	if err != nil {
+		return 0, err
+	}
+
+	w.logger.Infow("[WELCOME BONUS]", "user", userid, "amount", amount)
+	return amount, nil
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/benderr/gophermart/internal/domain/points"
+)
+
+type WelcomeRepo interface {
+	Reserve(ctx context.Context, amount float64, budget float64) (bool, error)
+}
+
+type BalanceRepo interface {
+	//возвращает сумму, зачисленную на баланс после погашения долга
+	Add(ctx context.Context, userid string, balance *float64) (float64, error)
+}
+
+type Points interface {
+	Credit(ctx context.Context, userid string, source points.Source, order string, credited float64, net float64) error
+}
@@ -0,0 +1,28 @@
+package welcome
+
+import "time"
+
+// Приветственный бонус при регистрации. Нулевые From/To не ограничивают период,
+// Budget=0 - без ограничения суммы всех выданных бонусов
+type Program struct {
+	Amount float64
+	From   time.Time
+	To     time.Time
+	Budget float64
+}
+
+func (p Program) Active(now time.Time) bool {
+	if p.Amount <= 0 {
+		return false
+	}
+
+	if !p.From.IsZero() && now.Before(p.From) {
+		return false
+	}
+
+	if !p.To.IsZero() && !now.Before(p.To) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,31 @@
+package welcome
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActive(t *testing.T) {
+	from := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		p      Program
+		now    time.Time
+		active bool
+	}{
+		{name: "disabled", p: Program{}, now: from, active: false},
+		{name: "no period", p: Program{Amount: 100}, now: from, active: true},
+		{name: "before period", p: Program{Amount: 100, From: from, To: to}, now: from.Add(-time.Second), active: false},
+		{name: "inside period", p: Program{Amount: 100, From: from, To: to}, now: from, active: true},
+		{name: "period ended", p: Program{Amount: 100, From: from, To: to}, now: to, active: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.active, tt.p.Active(tt.now))
+		})
+	}
+}
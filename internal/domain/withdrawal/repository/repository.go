@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/benderr/gophermart/internal/domain/balance"
 	"github.com/benderr/gophermart/internal/domain/withdrawal"
 	"github.com/benderr/gophermart/internal/logger"
+	"github.com/benderr/gophermart/internal/transactor"
 )
 
 type withdrawalRepository struct {
@@ -20,7 +22,7 @@ func New(db *sql.DB, log logger.Logger) *withdrawalRepository {
 func (w *withdrawalRepository) GetWithdrawsByUser(ctx context.Context, userid string) ([]withdrawal.Withdrawal, error) {
 	list := make([]withdrawal.Withdrawal, 0)
 
-	rows, err := w.db.QueryContext(ctx, "SELECT id, user_id, order_num, sum, processed_at from withdrawals WHERE user_id=$1 ORDER BY processed_at desc", userid)
+	rows, err := transactor.FromContext(ctx, w.db).QueryContext(ctx, "SELECT id, user_id, order_num, sum, processed_at from withdrawals WHERE user_id=$1 ORDER BY processed_at desc", userid)
 
 	if err != nil {
 		return nil, err
@@ -45,7 +47,14 @@ func (w *withdrawalRepository) GetWithdrawsByUser(ctx context.Context, userid st
 	return list, nil
 }
 
-func (w *withdrawalRepository) Create(ctx context.Context, tx *sql.Tx, userid string, order string, sum float64) error {
-	_, err := tx.ExecContext(ctx, `INSERT INTO withdrawals (user_id, order_num, sum) VALUES($1, $2, $3)`, userid, order, sum)
+func (w *withdrawalRepository) Create(ctx context.Context, userid string, order string, sum float64) error {
+	_, err := transactor.FromContext(ctx, w.db).ExecContext(ctx, `INSERT INTO withdrawals (user_id, order_num, sum) VALUES($1, $2, $3)`, userid, order, sum)
 	return err
 }
+
+func (w *withdrawalRepository) GetSumByPeriod(ctx context.Context, userid string, period balance.Period) (float64, error) {
+	row := transactor.FromContext(ctx, w.db).QueryRowContext(ctx, `SELECT COALESCE(SUM(sum), 0) FROM withdrawals WHERE user_id=$1 AND processed_at >= date_trunc($2, NOW())`, userid, string(period))
+	var sum float64
+	err := row.Scan(&sum)
+	return sum, err
+}
@@ -0,0 +1,12 @@
+package httputils
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Значение заголовка Retry-After в целых секундах, округленных вверх
+func RetryAfter(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
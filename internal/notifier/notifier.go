@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/benderr/gophermart/internal/logger"
+)
+
+// Сообщение пользователю. Канал доставки (почта, SMS) определяется реализацией
+type Message struct {
+	UserID    string    `json:"user_id"`
+	Login     string    `json:"login"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var ErrUnknownKind = errors.New("unknown notifier")
+
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+// Выбирает реализацию по настройке: log или file, пустая строка - уведомления не отправляются
+func New(kind string, path string, l logger.Logger) (Notifier, error) {
+	switch kind {
+	case "":
+		return NewDisabled(l), nil
+	case "log":
+		return NewLog(l), nil
+	case "file":
+		return NewFile(path), nil
+	}
+	return nil, ErrUnknownKind
+}
+
+type disabledNotifier struct {
+	logger logger.Logger
+}
+
+// Отбрасывает сообщения, в журнал пишет только получателя и тему без текста
+func NewDisabled(l logger.Logger) *disabledNotifier {
+	return &disabledNotifier{logger: l}
+}
+
+func (n *disabledNotifier) Notify(ctx context.Context, m Message) error {
+	n.logger.Infow("[NOTIFY DISABLED]", "login", m.Login, "subject", m.Subject)
+	return nil
+}
+
+type logNotifier struct {
+	logger logger.Logger
+}
+
+// Пишет сообщения в журнал сервиса вместе с текстом (включая токены сброса), только для локальной разработки
+func NewLog(l logger.Logger) *logNotifier {
+	return &logNotifier{logger: l}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, m Message) error {
+	n.logger.Infow("[NOTIFY]", "login", m.Login, "subject", m.Subject, "body", m.Body)
+	return nil
+}
+
+type fileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// Дописывает сообщения в файл построчно в JSON
+func NewFile(path string) *fileNotifier {
+	return &fileNotifier{path: path}
+}
+
+func (n *fileNotifier) Notify(ctx context.Context, m Message) error {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+
+	line, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
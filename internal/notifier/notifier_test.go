@@ -0,0 +1,55 @@
+package notifier_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mocklogger "github.com/benderr/gophermart/internal/logger/mock_logger"
+	"github.com/benderr/gophermart/internal/notifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	n := notifier.NewFile(path)
+
+	for _, subject := range []string{"first", "second"} {
+		err := n.Notify(context.Background(), notifier.Message{UserID: "testuserid", Subject: subject, Body: "body"})
+		assert.NoError(t, err)
+	}
+
+	f, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	subjects := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var m notifier.Message
+		if assert.NoError(t, json.Unmarshal(scanner.Bytes(), &m)) {
+			subjects = append(subjects, m.Subject)
+			assert.False(t, m.CreatedAt.IsZero())
+		}
+	}
+
+	assert.Equal(t, []string{"first", "second"}, subjects)
+}
+
+func TestNew(t *testing.T) {
+	_, err := notifier.New("log", "", mocklogger.New())
+	assert.NoError(t, err)
+
+	n, err := notifier.New("", "", mocklogger.New())
+	if assert.NoError(t, err) {
+		assert.NoError(t, n.Notify(context.Background(), notifier.Message{Subject: "subject", Body: "secret"}))
+	}
+
+	_, err = notifier.New("smtp", "", mocklogger.New())
+	assert.Equal(t, notifier.ErrUnknownKind, err)
+}
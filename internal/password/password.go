@@ -0,0 +1,160 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Algorithm string
+
+const (
+	Argon2id Algorithm = "argon2id"
+	Bcrypt   Algorithm = "bcrypt"
+)
+
+const (
+	saltLen = 16
+	keyLen  = 32
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown password hash algorithm")
+	//хеш не в формате PHC для argon2id и не в формате bcrypt
+	ErrInvalidHash = errors.New("invalid password hash")
+)
+
+// Параметры argon2id: память в KiB, число проходов и потоков
+type Argon2Params struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+type Config struct {
+	Algorithm  Algorithm
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+// Хеширует пароли выбранным алгоритмом. Проверяет хеши любого поддерживаемого алгоритма,
+// алгоритм и параметры берутся из самого хеша
+type Hasher struct {
+	conf Config
+}
+
+func New(conf Config) (*Hasher, error) {
+	switch conf.Algorithm {
+	case Argon2id:
+		if conf.Argon2.Memory == 0 || conf.Argon2.Time == 0 || conf.Argon2.Threads == 0 {
+			return nil, fmt.Errorf("invalid argon2id params: %+v", conf.Argon2)
+		}
+	case Bcrypt:
+		if conf.BcryptCost < bcrypt.MinCost || conf.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost: %d", conf.BcryptCost)
+		}
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+
+	return &Hasher{conf: conf}, nil
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.conf.Algorithm == Bcrypt {
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.conf.BcryptCost)
+		return string(bytes), err
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := h.conf.Argon2
+	key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, keyLen)
+	return encodeArgon2(p, salt, key), nil
+}
+
+// Несовпадение пароля не ошибка: возвращается false без ошибки
+func (h *Hasher) Verify(password, hash string) (bool, error) {
+	if isBcrypt(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	p, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+// Хеш получен другим алгоритмом или с другими параметрами и его нужно пересчитать
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if isBcrypt(hash) {
+		if h.conf.Algorithm != Bcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != h.conf.BcryptCost
+	}
+
+	if h.conf.Algorithm != Argon2id {
+		return true
+	}
+
+	p, _, _, err := decodeArgon2(hash)
+	return err != nil || p != h.conf.Argon2
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// Формат PHC: $argon2id$v=19$m=65536,t=1,p=2$<соль>$<ключ>, base64 без дополнения
+func encodeArgon2(p Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func decodeArgon2(hash string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != string(Argon2id) {
+		return p, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, ErrInvalidHash
+	}
+
+	return p, salt, key, nil
+}
@@ -0,0 +1,75 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benderr/gophermart/internal/password"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var argon2Conf = password.Config{
+	Algorithm: password.Argon2id,
+	Argon2:    password.Argon2Params{Memory: 1024, Time: 1, Threads: 1},
+}
+
+func TestArgon2id(t *testing.T) {
+	h, err := password.New(argon2Conf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	hash, err := h.Hash("secret")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.False(t, h.NeedsRehash(hash))
+
+	ok, err := h.Verify("secret", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify("wrong", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	stronger := argon2Conf
+	stronger.Argon2.Time = 2
+	h2, _ := password.New(stronger)
+	assert.True(t, h2.NeedsRehash(hash))
+
+	ok, err = h2.Verify("secret", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestLegacyBcrypt(t *testing.T) {
+	h, _ := password.New(argon2Conf)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ok, err := h.Verify("secret", string(legacy))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify("wrong", string(legacy))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.True(t, h.NeedsRehash(string(legacy)))
+}
+
+func TestInvalid(t *testing.T) {
+	_, err := password.New(password.Config{Algorithm: "md5"})
+	assert.ErrorIs(t, err, password.ErrUnknownAlgorithm)
+
+	h, _ := password.New(argon2Conf)
+	_, err = h.Verify("secret", "$argon2id$v=19$m=1024$bad")
+	assert.ErrorIs(t, err, password.ErrInvalidHash)
+}
@@ -0,0 +1,147 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+)
+
+var ErrBusy = errors.New("password hashing is busy")
+
+// Очередь на хеширование заполнена, повторить запрос можно через RetryAfter
+type BusyError struct {
+	RetryAfter time.Duration
+}
+
+func (e *BusyError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrBusy, e.RetryAfter)
+}
+
+func (e *BusyError) Unwrap() error {
+	return ErrBusy
+}
+
+// Ограничивает число одновременных вычислений хеша, чтобы всплеск входов не занимал все ядра.
+// Сверх workers ждут не больше queue запросов, остальные сразу получают BusyError
+type Pool struct {
+	hasher  *Hasher
+	workers chan struct{}
+	admit   chan struct{}
+	metrics *expvar.Map
+
+	queued    expvar.Int
+	running   expvar.Int
+	rejected  expvar.Int
+	waits     expvar.Int
+	waitTotal expvar.Int
+	waitMax   expvar.Int
+	completed expvar.Int
+	busyTotal expvar.Int
+}
+
+func NewPool(h *Hasher, workers int, queue int) *Pool {
+	p := &Pool{
+		hasher:  h,
+		workers: make(chan struct{}, workers),
+		admit:   make(chan struct{}, workers+queue),
+		metrics: new(expvar.Map).Init(),
+	}
+
+	p.metrics.Set("queued", &p.queued)
+	p.metrics.Set("running", &p.running)
+	p.metrics.Set("rejected", &p.rejected)
+	p.metrics.Set("wait_count", &p.waits)
+	p.metrics.Set("wait_total_us", &p.waitTotal)
+	p.metrics.Set("wait_max_us", &p.waitMax)
+	p.metrics.Set("completed", &p.completed)
+	p.metrics.Set("busy_total_us", &p.busyTotal)
+
+	return p
+}
+
+// Счетчики пула для публикации через expvar
+func (p *Pool) Metrics() expvar.Var {
+	return p.metrics
+}
+
+func (p *Pool) Hash(ctx context.Context, password string) (string, error) {
+	var hash string
+	var err error
+	if perr := p.run(ctx, func() { hash, err = p.hasher.Hash(password) }); perr != nil {
+		return "", perr
+	}
+	return hash, err
+}
+
+func (p *Pool) Verify(ctx context.Context, password, hash string) (bool, error) {
+	var ok bool
+	var err error
+	if perr := p.run(ctx, func() { ok, err = p.hasher.Verify(password, hash) }); perr != nil {
+		return false, perr
+	}
+	return ok, err
+}
+
+func (p *Pool) NeedsRehash(hash string) bool {
+	return p.hasher.NeedsRehash(hash)
+}
+
+func (p *Pool) run(ctx context.Context, f func()) error {
+	select {
+	case p.admit <- struct{}{}:
+	default:
+		p.rejected.Add(1)
+		return &BusyError{RetryAfter: p.retryAfter()}
+	}
+	defer func() { <-p.admit }()
+
+	start := time.Now()
+	p.queued.Add(1)
+	select {
+	case p.workers <- struct{}{}:
+		p.queued.Add(-1)
+	case <-ctx.Done():
+		p.queued.Add(-1)
+		return ctx.Err()
+	}
+	defer func() { <-p.workers }()
+
+	p.observeWait(time.Since(start))
+
+	p.running.Add(1)
+	start = time.Now()
+	f()
+	p.busyTotal.Add(time.Since(start).Microseconds())
+	p.completed.Add(1)
+	p.running.Add(-1)
+
+	return nil
+}
+
+func (p *Pool) observeWait(d time.Duration) {
+	us := d.Microseconds()
+	p.waits.Add(1)
+	p.waitTotal.Add(us)
+
+	//максимум обновляется без блокировки, точность здесь не важна
+	if us > p.waitMax.Value() {
+		p.waitMax.Set(us)
+	}
+}
+
+// Время, за которое пул разберет заполненную очередь, по среднему времени одного хеша.
+// Не меньше секунды
+func (p *Pool) retryAfter() time.Duration {
+	avg := 100 * time.Millisecond
+	if n := p.completed.Value(); n > 0 {
+		avg = time.Duration(p.busyTotal.Value()/n) * time.Microsecond
+	}
+
+	d := avg * time.Duration(cap(p.admit)/cap(p.workers))
+	if d < time.Second {
+		return time.Second
+	}
+	return d
+}
@@ -0,0 +1,66 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolQueueLimit(t *testing.T) {
+	p := NewPool(nil, 1, 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan error, 2)
+
+	go func() {
+		done <- p.run(context.Background(), func() {
+			close(started)
+			<-release
+		})
+	}()
+	<-started
+
+	go func() {
+		done <- p.run(context.Background(), func() {})
+	}()
+
+	assert.Eventually(t, func() bool { return p.queued.Value() == 1 }, time.Second, time.Millisecond)
+
+	err := p.run(context.Background(), func() {})
+	var busy *BusyError
+	if assert.True(t, errors.As(err, &busy)) {
+		assert.ErrorIs(t, err, ErrBusy)
+		assert.GreaterOrEqual(t, busy.RetryAfter, time.Second)
+	}
+	assert.Equal(t, int64(1), p.rejected.Value())
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+	assert.Equal(t, int64(2), p.completed.Value())
+	assert.Equal(t, int64(2), p.waits.Value())
+}
+
+func TestPoolCanceled(t *testing.T) {
+	p := NewPool(nil, 1, 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go p.run(context.Background(), func() {
+		close(started)
+		<-release
+	})
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := p.run(ctx, func() {})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int64(0), p.queued.Value())
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// Через сколько неиспользуемый лимитер ключа удаляется
+const idleTTL = 10 * time.Minute
+
+type entry struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// Ограничение частоты запросов по ключу (пользователь, IP): limit событий в секунду с запасом burst
+type Keyed struct {
+	mu      sync.Mutex
+	limit   rate.Limit
+	burst   int
+	entries map[string]*entry
+	cleaned time.Time
+}
+
+func New(limit rate.Limit, burst int) *Keyed {
+	return &Keyed{
+		limit:   limit,
+		burst:   burst,
+		entries: make(map[string]*entry),
+		cleaned: time.Now(),
+	}
+}
+
+// Частота n событий за период per
+func Every(n int, per time.Duration) rate.Limit {
+	if n <= 0 {
+		return rate.Inf
+	}
+	return rate.Every(per / time.Duration(n))
+}
+
+// Разрешено ли событие для ключа; если нет - через сколько повторить
+func (k *Keyed) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.cleanup(now)
+
+	e, ok := k.entries[key]
+	if !ok {
+		e = &entry{limiter: rate.NewLimiter(k.limit, k.burst)}
+		k.entries[key] = e
+	}
+	e.seen = now
+
+	r := e.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return false, time.Duration(math.MaxInt64)
+	}
+
+	delay := r.DelayFrom(now)
+	if delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+
+	return true, 0
+}
+
+func (k *Keyed) cleanup(now time.Time) {
+	if now.Sub(k.cleaned) < idleTTL {
+		return
+	}
+
+	for key, e := range k.entries {
+		if now.Sub(e.seen) > idleTTL {
+			delete(k.entries, key)
+		}
+	}
+	k.cleaned = now
+}
+
+// Middleware отвечает 429 с заголовком Retry-After, если для ключа запроса лимит исчерпан
+func Middleware(k *Keyed, key func(c echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ok, retry := k.Allow(key(c))
+			if !ok {
+				c.Response().Header().Set("Retry-After", httputils.RetryAfter(retry))
+				return c.JSON(http.StatusTooManyRequests, httputils.Error("too many requests"))
+			}
+			return next(c)
+		}
+	}
+}
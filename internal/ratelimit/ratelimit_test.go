@@ -0,0 +1,25 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllow(t *testing.T) {
+	k := New(Every(1, time.Minute), 2)
+
+	ok, _ := k.Allow("user")
+	assert.True(t, ok)
+	ok, _ = k.Allow("user")
+	assert.True(t, ok)
+
+	ok, retry := k.Allow("user")
+	assert.False(t, ok)
+	assert.Greater(t, retry, time.Duration(0))
+	assert.LessOrEqual(t, retry, time.Minute)
+
+	ok, _ = k.Allow("other")
+	assert.True(t, ok, "limits are per key")
+}
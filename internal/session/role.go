@@ -0,0 +1,45 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/labstack/echo/v4"
+)
+
+// Пропускает только пользователей с одной из ролей. Роль загружается из базы
+// вместе с сессией при разборе токена в TokenParser
+func (s *sessionManager) RequireRole(roles ...user.Role) echo.MiddlewareFunc {
+	allowed := make(map[user.Role]struct{}, len(roles))
+	for _, r := range roles {
+		allowed[r] = struct{}{}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, err := s.GetRole(c)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, httputils.Error("unauthorized"))
+			}
+
+			if _, ok := allowed[role]; !ok {
+				return c.JSON(http.StatusForbidden, httputils.Error("forbidden"))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func (s *sessionManager) GetRole(c echo.Context) (user.Role, error) {
+	claims, err := getClaims(c)
+	if err != nil {
+		return "", err
+	}
+
+	if claims.Role == "" {
+		return user.RoleUser, nil
+	}
+	return user.Role(claims.Role), nil
+}
@@ -1,53 +1,232 @@
 package session
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	"github.com/benderr/gophermart/internal/domain/token"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
+// Токен после проверки пароля, когда еще нужен код 2FA. Дает право только на второй шаг входа
+const (
+	ScopePreAuth = "pre_auth"
+	PreAuthTTL   = 5 * time.Minute
+)
+
+var (
+	ErrRevoked    = errors.New("session revoked")
+	ErrUnknownKey = errors.New("unknown signing key")
+	ErrScope      = errors.New("invalid token scope")
+)
+
 type sessionManager struct {
-	secret string
+	secret      string
+	keys        KeySource
+	accessTTL   time.Duration
+	legacyUntil time.Time
 }
 
 type UserClaims struct {
 	UserID string `json:"userid"`
+	//идентификатор серверной сессии, по нему проверяется отзыв токена
+	SessionID string `json:"sid"`
+	//при разборе токена заменяется ролью из серверной сессии
+	Role string `json:"role,omitempty"`
+	//ограничение токена, пусто - полный доступ
+	Scope string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func New(secret string) *sessionManager {
+// Асимметричные ключи подписи, ключ определяется по заголовку kid
+type KeySource interface {
+	SigningKey() (*signingkey.Key, error)
+	Key(kid string) (*signingkey.Key, bool)
+}
+
+// Серверная сессия токена: по ней проверяется отзыв и владелец
+type SessionChecker interface {
+	GetSession(ctx context.Context, sid string) (*token.Session, error)
+}
+
+// Без keys токены подписываются HS256 общим секретом. С keys подписываются асимметричным ключом,
+// а старые токены HS256 принимаются только до legacyUntil. С пустым секретом HS256 не принимается никогда
+func New(secret string, keys KeySource, accessTTL time.Duration, legacyUntil time.Time) *sessionManager {
 	return &sessionManager{
-		secret: secret,
+		secret:      secret,
+		keys:        keys,
+		accessTTL:   accessTTL,
+		legacyUntil: legacyUntil,
+	}
+}
+
+func (s *sessionManager) Create(userID string, sid string, role string) (string, error) {
+	return s.sign(UserClaims{
+		RegisteredClaims: registered(s.accessTTL),
+		UserID:           userID,
+		SessionID:        sid,
+		Role:             role,
+	})
+}
+
+func (s *sessionManager) CreatePreAuth(userID string) (string, error) {
+	return s.sign(UserClaims{
+		RegisteredClaims: registered(PreAuthTTL),
+		UserID:           userID,
+		Scope:            ScopePreAuth,
+	})
+}
+
+// Проверяет токен второго шага входа и возвращает пользователя
+func (s *sessionManager) ParsePreAuth(raw string) (string, error) {
+	claims, err := s.parse(raw)
+	if err != nil {
+		return "", err
+	}
+
+	if claims.Scope != ScopePreAuth {
+		return "", ErrScope
+	}
+
+	return claims.UserID, nil
+}
+
+func registered(ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+}
+
+func (s *sessionManager) sign(claims UserClaims) (string, error) {
+	if s.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(s.secret))
+	}
+
+	key, err := s.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+func (s *sessionManager) verificationKey(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Alg() || !s.acceptsHS256() {
+			return nil, ErrUnknownKey
+		}
+		return []byte(s.secret), nil
+	}
+
+	if s.keys == nil {
+		return nil, ErrUnknownKey
+	}
+
+	key, ok := s.keys.Key(kid)
+	if !ok || key.Method().Alg() != t.Method.Alg() {
+		return nil, ErrUnknownKey
+	}
+
+	return key.Public(), nil
+}
+
+func (s *sessionManager) acceptsHS256() bool {
+	if s.secret == "" {
+		return false
+	}
+	return s.keys == nil || time.Now().Before(s.legacyUntil)
+}
+
+// Разбор токена для echojwt: кроме подписи и срока проверяет, что сессия токена не отозвана
+// и принадлежит пользователю из токена
+func (s *sessionManager) TokenParser(checker SessionChecker) func(c echo.Context, auth string) (interface{}, error) {
+	return func(c echo.Context, auth string) (interface{}, error) {
+		parsed, err := s.parseToken(auth)
+		if err != nil {
+			return nil, err
+		}
+
+		claims := parsed.Claims.(*UserClaims)
+		if claims.Scope != "" {
+			return nil, ErrScope
+		}
+
+		if claims.SessionID == "" {
+			return nil, ErrRevoked
+		}
+
+		sess, err := checker.GetSession(c.Request().Context(), claims.SessionID)
+		if err != nil {
+			if errors.Is(err, token.ErrSessionNotFound) {
+				return nil, ErrRevoked
+			}
+			return nil, err
+		}
+
+		if sess.Revoked || sess.UserID != claims.UserID {
+			return nil, ErrRevoked
+		}
+
+		//роль в токене не используется для проверки доступа, берем текущую из базы
+		claims.Role = sess.Role
+
+		return parsed, nil
 	}
 }
 
-func (s *sessionManager) Create(userID string) (string, error) {
-	claims := UserClaims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
-		UserID: userID,
+func (s *sessionManager) parseToken(raw string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(raw, new(UserClaims), s.verificationKey, jwt.WithValidMethods([]string{
+		jwt.SigningMethodHS256.Alg(),
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	}))
+}
+
+func (s *sessionManager) parse(raw string) (*UserClaims, error) {
+	token, err := s.parseToken(raw)
+	if err != nil {
+		return nil, err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.secret))
+	return token.Claims.(*UserClaims), nil
 }
 
 func (s *sessionManager) GetUserID(c echo.Context) (string, error) {
+	claims, err := getClaims(c)
+	if err != nil {
+		return "", err
+	}
+	return claims.UserID, nil
+}
+
+func (s *sessionManager) GetSessionID(c echo.Context) (string, error) {
+	claims, err := getClaims(c)
+	if err != nil {
+		return "", err
+	}
+	return claims.SessionID, nil
+}
+
+func getClaims(c echo.Context) (*UserClaims, error) {
 	token, ok := c.Get("user").(*jwt.Token)
 	if !ok {
-		return "", errors.New("JWT token missing or invalid")
+		return nil, errors.New("JWT token missing or invalid")
 	}
 
 	if claims, ok := token.Claims.(*UserClaims); ok {
-		return claims.UserID, nil
+		return claims, nil
 	}
 
 	fmt.Printf("%T", token.Claims)
-	return "", errors.New("failed to cast claims as UserClaims")
-
+	return nil, errors.New("failed to cast claims as UserClaims")
 }
@@ -0,0 +1,168 @@
+package session_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/signingkey"
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/domain/user"
+	"github.com/benderr/gophermart/internal/session"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticKeys struct {
+	key *signingkey.Key
+}
+
+func (s staticKeys) SigningKey() (*signingkey.Key, error) {
+	return s.key, nil
+}
+
+func (s staticKeys) Key(kid string) (*signingkey.Key, bool) {
+	return s.key, kid == s.key.ID
+}
+
+type activeSessions map[string]token.Session
+
+func (a activeSessions) GetSession(ctx context.Context, sid string) (*token.Session, error) {
+	s, ok := a[sid]
+	if !ok {
+		return nil, token.ErrSessionNotFound
+	}
+	return &s, nil
+}
+
+func TestTokenParser(t *testing.T) {
+	key, err := signingkey.Generate(signingkey.EdDSA)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	legacy := session.New("secret", nil, time.Minute, time.Time{})
+	current := session.New("secret", staticKeys{key: key}, time.Minute, time.Now().Add(time.Hour))
+	parse := current.TokenParser(activeSessions{"sid": {ID: "sid", UserID: "testuserid", Role: "user"}})
+	c := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+
+	t.Run("Signed with kid", func(t *testing.T) {
+		signed, err := current.Create("testuserid", "sid", "user")
+		if assert.NoError(t, err) {
+			_, err = parse(c, signed)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("HS256 fallback", func(t *testing.T) {
+		signed, err := legacy.Create("testuserid", "sid", "user")
+		if assert.NoError(t, err) {
+			_, err = parse(c, signed)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("HS256 after cutoff", func(t *testing.T) {
+		expired := session.New("secret", staticKeys{key: key}, time.Minute, time.Now().Add(-time.Hour))
+		signed, err := legacy.Create("testuserid", "sid", "user")
+		if assert.NoError(t, err) {
+			_, err = expired.TokenParser(activeSessions{"sid": {ID: "sid", UserID: "testuserid"}})(c, signed)
+			assert.ErrorIs(t, err, session.ErrUnknownKey)
+		}
+	})
+
+	t.Run("HS256 with empty secret", func(t *testing.T) {
+		signed, err := session.New("", nil, time.Minute, time.Time{}).Create("testuserid", "sid", "admin")
+		if assert.NoError(t, err) {
+			noSecret := session.New("", staticKeys{key: key}, time.Minute, time.Now().Add(time.Hour))
+			_, err = noSecret.TokenParser(activeSessions{"sid": {ID: "sid", UserID: "testuserid"}})(c, signed)
+			assert.ErrorIs(t, err, session.ErrUnknownKey)
+		}
+	})
+
+	t.Run("Unknown kid", func(t *testing.T) {
+		other, err := signingkey.Generate(signingkey.EdDSA)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		signed, err := session.New("secret", staticKeys{key: other}, time.Minute, time.Time{}).Create("testuserid", "sid", "user")
+		if assert.NoError(t, err) {
+			_, err = parse(c, signed)
+			assert.ErrorIs(t, err, session.ErrUnknownKey)
+		}
+	})
+
+	t.Run("Revoked session", func(t *testing.T) {
+		signed, err := current.Create("testuserid", "revoked", "user")
+		if assert.NoError(t, err) {
+			_, err = parse(c, signed)
+			assert.ErrorIs(t, err, session.ErrRevoked)
+		}
+	})
+
+	t.Run("Session of another user", func(t *testing.T) {
+		signed, err := current.Create("otheruserid", "sid", "user")
+		if assert.NoError(t, err) {
+			_, err = parse(c, signed)
+			assert.ErrorIs(t, err, session.ErrRevoked)
+		}
+	})
+
+	t.Run("Pre-auth token rejected", func(t *testing.T) {
+		signed, err := current.CreatePreAuth("testuserid")
+		if assert.NoError(t, err) {
+			_, err = parse(c, signed)
+			assert.ErrorIs(t, err, session.ErrScope)
+
+			userid, err := current.ParsePreAuth(signed)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "testuserid", userid)
+			}
+		}
+	})
+
+	t.Run("Access token is not pre-auth", func(t *testing.T) {
+		signed, err := current.Create("testuserid", "sid", "user")
+		if assert.NoError(t, err) {
+			_, err = current.ParsePreAuth(signed)
+			assert.ErrorIs(t, err, session.ErrScope)
+		}
+	})
+}
+
+func TestRequireRole(t *testing.T) {
+	sm := session.New("secret", nil, time.Minute, time.Time{})
+	parse := sm.TokenParser(activeSessions{
+		"admin":   {ID: "admin", UserID: "testuserid", Role: "admin"},
+		"support": {ID: "support", UserID: "testuserid", Role: "support"},
+		"user":    {ID: "user", UserID: "testuserid", Role: "user"},
+	})
+	handler := sm.RequireRole(user.RoleSupport, user.RoleAdmin)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	//роль в токене всегда admin, доступ определяет роль сессии
+	call := func(sid string) int {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), rec)
+
+		if sid != "" {
+			signed, err := sm.Create("testuserid", sid, "admin")
+			assert.NoError(t, err)
+			token, err := parse(c, signed)
+			assert.NoError(t, err)
+			c.Set("user", token)
+		}
+
+		assert.NoError(t, handler(c))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, call("admin"))
+	assert.Equal(t, http.StatusOK, call("support"))
+	assert.Equal(t, http.StatusForbidden, call("user"))
+	assert.Equal(t, http.StatusUnauthorized, call(""))
+}
@@ -0,0 +1,147 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/benderr/gophermart/internal/domain/token"
+	"github.com/benderr/gophermart/internal/httputils"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	AccessCookie  = "access_token"
+	RefreshCookie = "refresh_token"
+	CSRFCookie    = "csrf_token"
+	CSRFHeader    = "X-CSRF-Token"
+)
+
+// Способ передачи токенов клиенту: заголовок Authorization или cookie
+type Transport interface {
+	//отдает выданную пару токенов в ответе
+	Respond(c echo.Context, pair *token.Pair) error
+	//токен обновления из запроса, пусто - клиент передает его в теле
+	RefreshToken(c echo.Context) string
+	//удаляет токены у клиента
+	Clear(c echo.Context)
+	//источники токена доступа для echojwt
+	TokenLookup() string
+	//дополнительная защита запросов, изменяющих состояние
+	CSRF() []echo.MiddlewareFunc
+}
+
+type headerTransport struct{}
+
+func NewHeaderTransport() Transport {
+	return headerTransport{}
+}
+
+func (headerTransport) Respond(c echo.Context, pair *token.Pair) error {
+	c.Response().Header().Add("Authorization", "Bearer "+pair.AccessToken)
+	return c.JSON(http.StatusOK, pair)
+}
+
+func (headerTransport) RefreshToken(c echo.Context) string {
+	return ""
+}
+
+func (headerTransport) Clear(c echo.Context) {}
+
+func (headerTransport) TokenLookup() string {
+	return "header:Authorization:Bearer "
+}
+
+func (headerTransport) CSRF() []echo.MiddlewareFunc {
+	return nil
+}
+
+// Токены в HttpOnly cookie. Токен доступа по-прежнему принимается и из заголовка,
+// для таких запросов проверка CSRF не нужна
+type cookieTransport struct {
+	secure     bool
+	refreshTTL time.Duration
+}
+
+func NewCookieTransport(secure bool, refreshTTL time.Duration) Transport {
+	return &cookieTransport{secure: secure, refreshTTL: refreshTTL}
+}
+
+func (t *cookieTransport) Respond(c echo.Context, pair *token.Pair) error {
+	csrf, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie(t.cookie(AccessCookie, pair.AccessToken, "/", time.Duration(pair.ExpiresIn)*time.Second, true))
+	c.SetCookie(t.cookie(RefreshCookie, pair.RefreshToken, "/api/user", t.refreshTTL, true))
+	//токен CSRF читает клиентский код и отправляет в заголовке X-CSRF-Token
+	c.SetCookie(t.cookie(CSRFCookie, csrf, "/", t.refreshTTL, false))
+
+	return c.JSON(http.StatusOK, &token.Pair{ExpiresIn: pair.ExpiresIn})
+}
+
+func (t *cookieTransport) RefreshToken(c echo.Context) string {
+	cookie, err := c.Cookie(RefreshCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func (t *cookieTransport) Clear(c echo.Context) {
+	c.SetCookie(t.cookie(AccessCookie, "", "/", -1, true))
+	c.SetCookie(t.cookie(RefreshCookie, "", "/api/user", -1, true))
+	c.SetCookie(t.cookie(CSRFCookie, "", "/", -1, false))
+}
+
+func (t *cookieTransport) TokenLookup() string {
+	return "header:Authorization:Bearer ,cookie:" + AccessCookie
+}
+
+// Double submit: значение заголовка должно совпадать со значением cookie csrf_token
+func (t *cookieTransport) CSRF() []echo.MiddlewareFunc {
+	return []echo.MiddlewareFunc{middleware.CSRFWithConfig(middleware.CSRFConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Request().Header.Get(echo.HeaderAuthorization) != ""
+		},
+		TokenLookup:    "header:" + CSRFHeader,
+		CookieName:     CSRFCookie,
+		CookiePath:     "/",
+		CookieMaxAge:   int(t.refreshTTL.Seconds()),
+		CookieSecure:   t.secure,
+		CookieSameSite: http.SameSiteStrictMode,
+		ErrorHandler: func(err error, c echo.Context) error {
+			return c.JSON(http.StatusForbidden, httputils.Error("invalid csrf token"))
+		},
+	})}
+}
+
+func (t *cookieTransport) cookie(name, value, path string, ttl time.Duration, httpOnly bool) *http.Cookie {
+	c := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Secure:   t.secure,
+		HttpOnly: httpOnly,
+		SameSite: http.SameSiteStrictMode,
+	}
+
+	if ttl < 0 {
+		c.MaxAge = -1
+	} else {
+		c.MaxAge = int(ttl.Seconds())
+	}
+
+	return c
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
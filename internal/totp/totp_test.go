@@ -0,0 +1,88 @@
+package totp_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/benderr/gophermart/internal/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+// Секрет "12345678901234567890" из приложения B RFC 6238
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	//эталонные коды RFC 6238 для SHA1, последние шесть цифр восьмизначных значений
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := totp.Code(rfcSecret, time.Unix(tt.unix, 0))
+		if assert.NoError(t, err) {
+			assert.Equal(t, tt.want, code, "time %d", tt.unix)
+		}
+	}
+
+	_, err := totp.Code("not base32!", time.Now())
+	assert.Equal(t, totp.ErrInvalidSecret, err)
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	t.Run("Current step", func(t *testing.T) {
+		counter, ok := totp.Verify(rfcSecret, "050471", now, 1)
+		assert.True(t, ok)
+		assert.Equal(t, totp.Counter(now), counter)
+	})
+
+	t.Run("Previous step within skew", func(t *testing.T) {
+		counter, ok := totp.Verify(rfcSecret, "050471", now.Add(totp.Period), 1)
+		assert.True(t, ok)
+		assert.Equal(t, totp.Counter(now), counter)
+	})
+
+	t.Run("Outside skew", func(t *testing.T) {
+		_, ok := totp.Verify(rfcSecret, "050471", now.Add(3*totp.Period), 1)
+		assert.False(t, ok)
+	})
+
+	t.Run("Wrong code", func(t *testing.T) {
+		_, ok := totp.Verify(rfcSecret, "000000", now, 1)
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if assert.NoError(t, err) {
+		assert.Len(t, secret, 32)
+
+		code, err := totp.Code(secret, time.Now())
+		assert.NoError(t, err)
+		assert.Len(t, code, totp.Digits)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("Gophermart", "user@example.com", rfcSecret)
+
+	u, err := url.Parse(uri)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "otpauth", u.Scheme)
+		assert.Equal(t, "totp", u.Host)
+		assert.Equal(t, "/Gophermart:user@example.com", u.Path)
+		assert.Equal(t, rfcSecret, u.Query().Get("secret"))
+		assert.Equal(t, "Gophermart", u.Query().Get("issuer"))
+	}
+}
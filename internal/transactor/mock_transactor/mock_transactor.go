@@ -2,7 +2,8 @@ package mocktransactor
 
 import (
 	"context"
-	"database/sql"
+
+	"github.com/benderr/gophermart/internal/transactor"
 )
 
 type MockTransactor struct {
@@ -12,6 +13,6 @@ func New() *MockTransactor {
 	return &MockTransactor{}
 }
 
-func (m *MockTransactor) Within(ctx context.Context, tFunc func(ctx context.Context, tx *sql.Tx) error) error {
-	return tFunc(ctx, nil)
+func (m *MockTransactor) Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...transactor.Option) error {
+	return tFunc(ctx)
 }
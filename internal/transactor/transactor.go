@@ -3,18 +3,117 @@ package transactor
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	defaultAttempts = 5
+	baseBackoff     = 20 * time.Millisecond
+	maxBackoff      = 500 * time.Millisecond
 )
 
 type transactorInstance struct {
 	db *sql.DB
 }
 
+type txKey struct{}
+
+// Транзакция в контексте и глубина вложенности Within
+type txState struct {
+	tx    *sql.Tx
+	depth int
+}
+
+// Общий набор методов *sql.DB и *sql.Tx, которым пользуются репозитории
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Возвращает транзакцию, открытую в Within и переданную через ctx, либо db, если транзакции нет
+func FromContext(ctx context.Context, db *sql.DB) Executor {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return db
+}
+
+type options struct {
+	isolation sql.IsolationLevel
+	readOnly  bool
+	attempts  int
+}
+
+type Option func(o *options)
+
+// Уровень изоляции транзакции, по умолчанию используется уровень СУБД (READ COMMITTED)
+func WithIsolation(level sql.IsolationLevel) Option {
+	return func(o *options) {
+		o.isolation = level
+	}
+}
+
+func ReadOnly() Option {
+	return func(o *options) {
+		o.readOnly = true
+	}
+}
+
+// Количество попыток выполнить транзакцию при ошибках сериализации и взаимных блокировках
+func WithAttempts(attempts int) Option {
+	return func(o *options) {
+		if attempts > 0 {
+			o.attempts = attempts
+		}
+	}
+}
+
 func New(db *sql.DB) *transactorInstance {
 	return &transactorInstance{db}
 }
 
-func (t *transactorInstance) Within(ctx context.Context, tFunc func(ctx context.Context, tx *sql.Tx) error) error {
-	tx, err := t.db.Begin()
+// Выполняет tFunc в транзакции, которая доступна репозиториям через FromContext(ctx).
+// При конфликте сериализации или взаимной блокировке транзакция откатывается
+// и tFunc выполняется заново с растущей задержкой.
+// Вложенный вызов Within выполняется в точке сохранения внешней транзакции: ошибка
+// откатывает только изменения вложенного вызова, фиксирует и повторяет транзакцию только внешний уровень.
+// Опции вложенного вызова не применяются, действуют опции внешней транзакции
+func (t *transactorInstance) Within(ctx context.Context, tFunc func(ctx context.Context) error, opts ...Option) error {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return withinSavepoint(ctx, state, tFunc)
+	}
+
+	o := &options{
+		isolation: sql.LevelDefault,
+		attempts:  defaultAttempts,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = t.within(ctx, tFunc, o)
+		if err == nil || !isRetryable(err) || attempt >= o.attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff(attempt)):
+		}
+	}
+}
+
+func (t *transactorInstance) within(ctx context.Context, tFunc func(ctx context.Context) error, o *options) error {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{Isolation: o.isolation, ReadOnly: o.readOnly})
 	if err != nil {
 		return err
 	}
@@ -25,7 +124,7 @@ func (t *transactorInstance) Within(ctx context.Context, tFunc func(ctx context.
 		}
 	}()
 
-	err = tFunc(ctx, tx)
+	err = tFunc(context.WithValue(ctx, txKey{}, &txState{tx: tx}))
 
 	if err != nil {
 		tx.Rollback()
@@ -34,3 +133,51 @@ func (t *transactorInstance) Within(ctx context.Context, tFunc func(ctx context.
 
 	return tx.Commit()
 }
+
+func withinSavepoint(ctx context.Context, state *txState, tFunc func(ctx context.Context) error) error {
+	nested := &txState{tx: state.tx, depth: state.depth + 1}
+	name := savepointName(nested.depth)
+
+	_, err := state.tx.ExecContext(ctx, "SAVEPOINT "+name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	err = tFunc(context.WithValue(ctx, txKey{}, nested))
+
+	if err != nil {
+		if _, rerr := state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+
+	_, err = state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+func savepointName(depth int) string {
+	return fmt.Sprintf("sp_%d", depth)
+}
+
+func isRetryable(err error) bool {
+	var perr *pgconn.PgError
+	if errors.As(err, &perr) {
+		return perr.Code == pgerrcode.SerializationFailure || perr.Code == pgerrcode.DeadlockDetected
+	}
+	return false
+}
+
+func backoff(attempt int) time.Duration {
+	d := baseBackoff << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
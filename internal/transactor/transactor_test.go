@@ -0,0 +1,153 @@
+package transactor
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, isRetryable(&pgconn.PgError{Code: pgerrcode.SerializationFailure}))
+	assert.True(t, isRetryable(fmt.Errorf("commit: %w", &pgconn.PgError{Code: pgerrcode.DeadlockDetected})))
+	assert.False(t, isRetryable(&pgconn.PgError{Code: pgerrcode.UniqueViolation}))
+	assert.False(t, isRetryable(errors.New("some error")))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, baseBackoff, backoff(1))
+	assert.Equal(t, 2*baseBackoff, backoff(2))
+	assert.Equal(t, maxBackoff, backoff(10))
+	assert.Equal(t, maxBackoff, backoff(100))
+
+	for attempt := 1; attempt < 100; attempt++ {
+		assert.LessOrEqual(t, backoff(attempt), maxBackoff)
+		assert.Greater(t, backoff(attempt), time.Duration(0))
+	}
+}
+
+// Драйвер, который только записывает выполняемые команды
+type recordDriver struct {
+	log *[]string
+}
+
+type recordConn struct {
+	log *[]string
+}
+
+type recordTx struct {
+	log *[]string
+}
+
+func (d *recordDriver) Open(name string) (driver.Conn, error) {
+	return &recordConn{log: d.log}, nil
+}
+
+func (c *recordConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *recordConn) Close() error {
+	return nil
+}
+
+func (c *recordConn) Begin() (driver.Tx, error) {
+	*c.log = append(*c.log, "BEGIN")
+	return &recordTx{log: c.log}, nil
+}
+
+func (c *recordConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	*c.log = append(*c.log, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (t *recordTx) Commit() error {
+	*t.log = append(*t.log, "COMMIT")
+	return nil
+}
+
+func (t *recordTx) Rollback() error {
+	*t.log = append(*t.log, "ROLLBACK")
+	return nil
+}
+
+func newRecordDB(t *testing.T) (*sql.DB, *[]string) {
+	log := make([]string, 0)
+	db := sql.OpenDB(&recordConnector{driver: &recordDriver{log: &log}})
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db, &log
+}
+
+type recordConnector struct {
+	driver *recordDriver
+}
+
+func (c *recordConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c *recordConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func TestWithinNested(t *testing.T) {
+	errInner := errors.New("inner failed")
+
+	t.Run("Inner error rolls back to savepoint", func(t *testing.T) {
+		db, log := newRecordDB(t)
+		tr := New(db)
+
+		err := tr.Within(context.Background(), func(ctx context.Context) error {
+			FromContext(ctx, db).ExecContext(ctx, "outer")
+
+			innerErr := tr.Within(ctx, func(ctx context.Context) error {
+				FromContext(ctx, db).ExecContext(ctx, "inner")
+				return errInner
+			})
+			assert.ErrorIs(t, innerErr, errInner)
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"BEGIN", "outer", "SAVEPOINT sp_1", "inner", "ROLLBACK TO SAVEPOINT sp_1", "COMMIT"}, *log)
+	})
+
+	t.Run("Nested success releases savepoints", func(t *testing.T) {
+		db, log := newRecordDB(t)
+		tr := New(db)
+
+		err := tr.Within(context.Background(), func(ctx context.Context) error {
+			return tr.Within(ctx, func(ctx context.Context) error {
+				return tr.Within(ctx, func(ctx context.Context) error {
+					_, err := FromContext(ctx, db).ExecContext(ctx, "deep")
+					return err
+				})
+			})
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"BEGIN", "SAVEPOINT sp_1", "SAVEPOINT sp_2", "deep", "RELEASE SAVEPOINT sp_2", "RELEASE SAVEPOINT sp_1", "COMMIT"}, *log)
+	})
+
+	t.Run("Outer error rolls back transaction", func(t *testing.T) {
+		db, log := newRecordDB(t)
+		tr := New(db)
+
+		err := tr.Within(context.Background(), func(ctx context.Context) error {
+			tr.Within(ctx, func(ctx context.Context) error { return nil })
+			return errInner
+		})
+
+		assert.ErrorIs(t, err, errInner)
+		assert.Equal(t, []string{"BEGIN", "SAVEPOINT sp_1", "RELEASE SAVEPOINT sp_1", "ROLLBACK"}, *log)
+	})
+}